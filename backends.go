@@ -0,0 +1,251 @@
+package ionq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-querystring/query"
+)
+
+const (
+	backendsPath         = "backends"
+	characterizationPath = "characterizations/backends"
+)
+
+// BackendStatus is the status of a backend as reported by the IonQ API.
+type BackendStatus string
+
+const (
+	BackendStatusAvailable   BackendStatus = "available"
+	BackendStatusUnavailable BackendStatus = "unavailable"
+	BackendStatusCalibrating BackendStatus = "calibrating"
+	BackendStatusOffline     BackendStatus = "offline"
+	BackendStatusRetired     BackendStatus = "retired"
+)
+
+type Backend struct {
+	Backend Target        `json:"backend,omitempty"`
+	Status  BackendStatus `json:"status,omitempty"`
+	Qubits  int           `json:"qubits,omitempty"`
+
+	// AverageQueueTime is in milliseconds
+	AverageQueueTime int64 `json:"average_queue_time,omitempty"`
+
+	// LastUpdated is a unix timestamp in seconds
+	LastUpdated int64 `json:"last_updated,omitempty"`
+
+	Degraded            bool         `json:"degraded,omitempty"`
+	HasAccess           bool         `json:"has_access,omitempty"`
+	CharacterizationURL string       `json:"characterization_url,omitempty"`
+	NoiseModels         []NoiseModel `json:"noise_models,omitempty"`
+}
+
+// IsAvailable reports whether the backend accepts jobs.
+func (b *Backend) IsAvailable() bool {
+	return b.Status == BackendStatusAvailable
+}
+
+// QueueTime returns the average time jobs wait in the backend's queue.
+func (b *Backend) QueueTime() time.Duration {
+	return time.Duration(b.AverageQueueTime) * time.Millisecond
+}
+
+// LastUpdatedTime returns the time the backend's status was last updated.
+func (b *Backend) LastUpdatedTime() time.Time {
+	return time.Unix(b.LastUpdated, 0)
+}
+
+type GetBackendsResponse []Backend
+
+type GetBackendsResponseWithStatus struct {
+	Response GetBackendsResponse
+	Status   int
+}
+
+// FidelityStat is a fidelity measured on a backend.
+type FidelityStat struct {
+	Mean   float64 `json:"mean,omitempty"`
+	Stderr float64 `json:"stderr,omitempty"`
+}
+
+type Fidelity struct {
+	SPAM FidelityStat `json:"spam,omitempty"`
+	OneQ FidelityStat `json:"1q,omitempty"`
+	TwoQ FidelityStat `json:"2q,omitempty"`
+}
+
+// Timing holds the durations of a backend's operations, in seconds.
+type Timing struct {
+	T1      float64 `json:"t1,omitempty"`
+	T2      float64 `json:"t2,omitempty"`
+	OneQ    float64 `json:"1q,omitempty"`
+	TwoQ    float64 `json:"2q,omitempty"`
+	Readout float64 `json:"readout,omitempty"`
+	Reset   float64 `json:"reset,omitempty"`
+}
+
+type Characterization struct {
+	ID      string `json:"id,omitempty"`
+	Backend Target `json:"backend,omitempty"`
+	Qubits  int    `json:"qubits,omitempty"`
+
+	// Date is a unix timestamp in seconds
+	Date int64 `json:"date,omitempty"`
+
+	// Connectivity lists the pairs of qubits two-qubit gates can act on
+	Connectivity [][2]uint `json:"connectivity,omitempty"`
+	Fidelity     Fidelity  `json:"fidelity,omitempty"`
+	Timing       Timing    `json:"timing,omitempty"`
+}
+
+// DateTime returns the time the characterization was measured.
+func (c *Characterization) DateTime() time.Time {
+	return time.Unix(c.Date, 0)
+}
+
+// IsConnected reports whether a two-qubit gate can act on qubits a and b.
+// Backends without a connectivity list are fully connected.
+func (c *Characterization) IsConnected(a, b uint) bool {
+	if a == b || int(max(a, b)) >= c.Qubits {
+		return false
+	}
+
+	if len(c.Connectivity) == 0 {
+		return true
+	}
+
+	for _, pair := range c.Connectivity {
+		if (pair[0] == a && pair[1] == b) || (pair[0] == b && pair[1] == a) {
+			return true
+		}
+	}
+	return false
+}
+
+type GetCharacterizationRequest struct {
+	Backend Target `url:"-"`
+}
+
+type GetCharacterizationResponse Characterization
+
+type GetCharacterizationResponseWithStatus struct {
+	Response GetCharacterizationResponse
+	Status   int
+}
+
+// GetCharacterizationsRequest selects the characterizations of a backend
+// measured between Start and End. Zero times are left out of the query.
+type GetCharacterizationsRequest struct {
+	Backend Target    `url:"-"`
+	Start   time.Time `url:"start,unix,omitempty"`
+	End     time.Time `url:"end,unix,omitempty"`
+	Limit   uint      `url:"limit,omitempty"`
+	Page    uint      `url:"page,omitempty"`
+}
+
+type GetCharacterizationsResponse struct {
+	Characterizations []Characterization `json:"characterizations,omitempty"`
+	Pages             int                `json:"pages,omitempty"`
+}
+
+type GetCharacterizationsResponseWithStatus struct {
+	Response GetCharacterizationsResponse
+	Status   int
+}
+
+// GetBackends retrieves the backends available through the IonQ API and
+// returns the response according to the API documentation.
+func (c *Client) GetBackends(ctx context.Context) (*GetBackendsResponseWithStatus, error) {
+	url := c.makeURL(backendsPath)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	body, status, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var backendsResponse GetBackendsResponse
+	if err := json.Unmarshal(body, &backendsResponse); err != nil {
+		return nil, err
+	}
+
+	return &GetBackendsResponseWithStatus{
+		Response: backendsResponse,
+		Status:   status,
+	}, nil
+}
+
+// GetCharacterization retrieves the current characterization of a backend
+// from the IonQ API and returns the response according to the API
+// documentation.
+func (c *Client) GetCharacterization(ctx context.Context, getCharacterizationRequest *GetCharacterizationRequest) (*GetCharacterizationResponseWithStatus, error) {
+	url := c.makeURL(fmt.Sprintf("%s/%s/current", characterizationPath, getCharacterizationRequest.Backend))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	body, status, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var characterizationResponse GetCharacterizationResponse
+	if err := json.Unmarshal(body, &characterizationResponse); err != nil {
+		return nil, err
+	}
+
+	return &GetCharacterizationResponseWithStatus{
+		Response: characterizationResponse,
+		Status:   status,
+	}, nil
+}
+
+// GetCharacterizations retrieves the characterizations of a backend from the
+// IonQ API and returns the response according to the API documentation.
+func (c *Client) GetCharacterizations(ctx context.Context, getCharacterizationsRequest *GetCharacterizationsRequest) (*GetCharacterizationsResponseWithStatus, error) {
+	url := c.makeURL(fmt.Sprintf("%s/%s", characterizationPath, getCharacterizationsRequest.Backend))
+
+	v, err := query.Values(getCharacterizationsRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v) > 0 {
+		url += fmt.Sprintf("?%s", v.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req)
+
+	body, status, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var characterizationsResponse GetCharacterizationsResponse
+	if err := json.Unmarshal(body, &characterizationsResponse); err != nil {
+		return nil, err
+	}
+
+	return &GetCharacterizationsResponseWithStatus{
+		Response: characterizationsResponse,
+		Status:   status,
+	}, nil
+}
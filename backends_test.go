@@ -0,0 +1,196 @@
+package ionq
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/go-test/deep"
+	"github.com/h2non/gock"
+)
+
+func TestGetBackendsSuccess(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	var backendsResponse GetBackendsResponse
+	gofakeit.Slice(&backendsResponse)
+
+	newGock().
+		Get(backendsPath).
+		Reply(200).
+		JSON(&backendsResponse)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	backendsWithStatus, err := client.GetBackends(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if backendsWithStatus.Status != http.StatusOK {
+		t.Fatalf("unexpected status: %d", backendsWithStatus.Status)
+	}
+
+	if diff := deep.Equal(backendsResponse, backendsWithStatus.Response); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestGetBackendsDecode(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(backendsPath).
+		Reply(200).
+		BodyString(`[{"backend": "qpu.aria-1", "status": "available", "qubits": 25, "average_queue_time": 1500, "last_updated": 1700000000, "noise_models": ["aria-1"]}]`)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	backendsWithStatus, err := client.GetBackends(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backendsWithStatus.Response) != 1 {
+		t.Fatalf("unexpected backends: %+v", backendsWithStatus.Response)
+	}
+
+	backend := backendsWithStatus.Response[0]
+	if !backend.IsAvailable() || backend.Qubits != 25 {
+		t.Fatalf("unexpected backend: %+v", backend)
+	}
+
+	if backend.QueueTime() != 1500*time.Millisecond {
+		t.Fatalf("unexpected queue time: %s", backend.QueueTime())
+	}
+
+	if !backend.LastUpdatedTime().Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("unexpected last updated: %s", backend.LastUpdatedTime())
+	}
+}
+
+func TestGetCharacterizationSuccess(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/qpu.aria-1/current", characterizationPath)).
+		Reply(200).
+		BodyString(`{
+			"id": "some-id",
+			"backend": "qpu.aria-1",
+			"date": 1700000000,
+			"qubits": 3,
+			"connectivity": [[0, 1], [1, 2]],
+			"fidelity": {"spam": {"mean": 0.99, "stderr": 0.001}, "1q": {"mean": 0.9998}, "2q": {"mean": 0.98}},
+			"timing": {"t1": 100, "t2": 1, "1q": 0.000135, "2q": 0.0006, "readout": 0.0003, "reset": 0.00002}
+		}`)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	characterizationWithStatus, err := client.GetCharacterization(ctx, &GetCharacterizationRequest{Backend: "qpu.aria-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := GetCharacterizationResponse{
+		ID:           "some-id",
+		Backend:      "qpu.aria-1",
+		Date:         1700000000,
+		Qubits:       3,
+		Connectivity: [][2]uint{{0, 1}, {1, 2}},
+		Fidelity: Fidelity{
+			SPAM: FidelityStat{Mean: 0.99, Stderr: 0.001},
+			OneQ: FidelityStat{Mean: 0.9998},
+			TwoQ: FidelityStat{Mean: 0.98},
+		},
+		Timing: Timing{T1: 100, T2: 1, OneQ: 0.000135, TwoQ: 0.0006, Readout: 0.0003, Reset: 0.00002},
+	}
+
+	if diff := deep.Equal(expected, characterizationWithStatus.Response); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestCharacterizationIsConnected(t *testing.T) {
+	characterization := Characterization{Qubits: 3, Connectivity: [][2]uint{{0, 1}, {1, 2}}}
+
+	for _, tc := range []struct {
+		a, b      uint
+		connected bool
+	}{
+		{0, 1, true},
+		{2, 1, true},
+		{0, 2, false},
+		{1, 1, false},
+		{2, 3, false},
+	} {
+		if characterization.IsConnected(tc.a, tc.b) != tc.connected {
+			t.Fatalf("unexpected connectivity for %d, %d", tc.a, tc.b)
+		}
+	}
+
+	allToAll := Characterization{Qubits: 3}
+	if !allToAll.IsConnected(0, 2) {
+		t.Fatal("expected characterization without connectivity to be fully connected")
+	}
+}
+
+func TestGetCharacterizationsSuccess(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	var characterizationsResponse GetCharacterizationsResponse
+	if err := gofakeit.Struct(&characterizationsResponse); err != nil {
+		t.Fatal(err)
+	}
+
+	newGock().
+		Get(fmt.Sprintf("%s/qpu.aria-1", characterizationPath)).
+		MatchParam("start", "1700000000").
+		MatchParam("end", "1700086400").
+		MatchParam("limit", "10").
+		Reply(200).
+		JSON(&characterizationsResponse)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	characterizationsWithStatus, err := client.GetCharacterizations(ctx, &GetCharacterizationsRequest{
+		Backend: "qpu.aria-1",
+		Start:   time.Unix(1700000000, 0),
+		End:     time.Unix(1700086400, 0),
+		Limit:   10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(characterizationsResponse, characterizationsWithStatus.Response); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestGetCharacterizationFailure(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/qpu.unknown/current", characterizationPath)).
+		Reply(404).
+		JSON(map[string]any{"error": map[string]string{"type": "NotFoundError", "message": "backend not found"}})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := client.GetCharacterization(ctx, &GetCharacterizationRequest{Backend: "qpu.unknown"})
+	assertAPIError(t, err, http.StatusNotFound, ErrNotFound)
+}
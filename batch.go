@@ -0,0 +1,168 @@
+package ionq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// batchConcurrency is the number of child jobs GetJobOutputs fetches at once.
+const batchConcurrency = 4
+
+// BatchCircuit is a circuit of a batch job, built on its own with NewCircuit
+// or NewNativeCircuit.
+type BatchCircuit struct {
+	Name  string
+	Input JobInput
+}
+
+// NewBatchInput merges circuits into the JobInput of a multicircuit job. The
+// circuits must have unique names and share a gate set; the job uses as many
+// qubits as the largest circuit.
+func NewBatchInput(circuits ...BatchCircuit) (JobInput, error) {
+	if len(circuits) == 0 {
+		return JobInput{}, fmt.Errorf("%w: batch requires at least one circuit", ErrInvalidCircuit)
+	}
+
+	input := JobInput{
+		Format:   CircuitFormat,
+		Gateset:  circuits[0].Input.Gateset,
+		Circuits: make([]NamedCircuit, len(circuits)),
+	}
+
+	names := make(map[string]bool, len(circuits))
+	for i, circuit := range circuits {
+		if circuit.Name == "" || names[circuit.Name] {
+			return JobInput{}, fmt.Errorf("%w: circuit %d requires a unique name", ErrInvalidCircuit, i)
+		}
+		names[circuit.Name] = true
+
+		if circuit.Input.Format == FormatOpenQASM || len(circuit.Input.Circuits) > 0 {
+			return JobInput{}, fmt.Errorf("%w: circuit %q must be a single circuit", ErrInvalidCircuit, circuit.Name)
+		}
+
+		if err := circuit.Input.Validate(); err != nil {
+			return JobInput{}, fmt.Errorf("circuit %q: %w", circuit.Name, err)
+		}
+
+		if circuit.Input.Gateset != input.Gateset {
+			return JobInput{}, fmt.Errorf("%w: circuit %q uses the %q gate set, expected %q", ErrInvalidCircuit, circuit.Name, circuit.Input.Gateset, input.Gateset)
+		}
+
+		input.Qubits = max(input.Qubits, circuit.Input.Qubits)
+		input.Circuits[i] = NamedCircuit{Name: circuit.Name, Circuit: circuit.Input.Circuit}
+	}
+
+	return input, nil
+}
+
+// CreateBatchJob creates a multicircuit job running every circuit with the
+// settings of createJobRequest, whose Input is replaced by the circuits. The
+// API creates a child job for each circuit, named after it.
+func (c *Client) CreateBatchJob(ctx context.Context, createJobRequest *CreateJobRequest, circuits ...BatchCircuit) (*CreateJobResponseWithStatus, error) {
+	input, err := NewBatchInput(circuits...)
+	if err != nil {
+		return nil, err
+	}
+
+	batchRequest := *createJobRequest
+	batchRequest.Input = input
+
+	return c.CreateJob(ctx, &batchRequest)
+}
+
+// GetJobOutputs retrieves the output of every child of a multicircuit job,
+// keyed by the name of its circuit. Children without a name, or sharing a
+// name with an earlier child, are keyed by their ID. A job without children
+// returns its own output, keyed the same way.
+//
+// Every child must be completed; a failed or canceled child is returned as a
+// *JobError.
+func (c *Client) GetJobOutputs(ctx context.Context, id string) (map[string]GetJobOutputResponse, error) {
+	jobResponseWithStatus, err := c.GetJob(ctx, &GetJobRequest{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	children := jobResponseWithStatus.Response.Children
+	if len(children) == 0 {
+		children = []string{id}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	names := make([]string, len(children))
+	outputs := make([]GetJobOutputResponse, len(children))
+	sem := make(chan struct{}, batchConcurrency)
+
+	for i, child := range children {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var err error
+			names[i], outputs[i], err = c.childOutput(ctx, child)
+			if err != nil {
+				// keep the error that stopped the other children
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	results := make(map[string]GetJobOutputResponse, len(children))
+	for i, name := range names {
+		if _, ok := results[name]; ok || name == "" {
+			name = children[i]
+		}
+		results[name] = outputs[i]
+	}
+
+	return results, nil
+}
+
+func (c *Client) childOutput(ctx context.Context, id string) (string, GetJobOutputResponse, error) {
+	jobResponseWithStatus, err := c.GetJob(ctx, &GetJobRequest{ID: id})
+	if err != nil {
+		return "", nil, fmt.Errorf("child job %s: %w", id, err)
+	}
+
+	job := Job(jobResponseWithStatus.Response)
+
+	switch job.Status {
+	case JobStatusCompleted:
+	case JobStatusFailed, JobStatusCanceled:
+		return "", nil, &JobError{
+			Job:     job,
+			Status:  job.Status,
+			Code:    job.Failure.Code,
+			Message: job.Failure.Error,
+		}
+	default:
+		return "", nil, fmt.Errorf("child job %s is %s, not completed", id, job.Status)
+	}
+
+	outputResponseWithStatus, err := c.GetJobOutput(ctx, &GetJobOutputRequest{ID: id})
+	if err != nil {
+		return "", nil, fmt.Errorf("child job %s: %w", id, err)
+	}
+
+	return job.Name, outputResponseWithStatus.Response, nil
+}
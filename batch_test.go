@@ -0,0 +1,201 @@
+package ionq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/h2non/gock"
+)
+
+func testBatchCircuits(t *testing.T) []BatchCircuit {
+	t.Helper()
+
+	var circuits []BatchCircuit
+	for i, theta := range []float64{0, math.Pi / 2} {
+		input, err := NewCircuit(uint(i+1)).RX(uint(i), theta).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		circuits = append(circuits, BatchCircuit{Name: fmt.Sprintf("theta-%d", i), Input: input})
+	}
+	return circuits
+}
+
+func TestNewBatchInput(t *testing.T) {
+	circuits := testBatchCircuits(t)
+
+	input, err := NewBatchInput(circuits...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := JobInput{
+		Format:  CircuitFormat,
+		Gateset: GatesetQIS,
+		Qubits:  2,
+		Circuits: []NamedCircuit{
+			{Name: "theta-0", Circuit: circuits[0].Input.Circuit},
+			{Name: "theta-1", Circuit: circuits[1].Input.Circuit},
+		},
+	}
+
+	if diff := deep.Equal(expected, input); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if err := input.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewBatchInputErrors(t *testing.T) {
+	circuits := testBatchCircuits(t)
+
+	native, err := NewNativeCircuit(1).GPI(0, 0).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		circuits []BatchCircuit
+	}{
+		{"empty", nil},
+		{"duplicate name", []BatchCircuit{circuits[0], {Name: "theta-0", Input: circuits[1].Input}}},
+		{"missing name", []BatchCircuit{{Input: circuits[0].Input}}},
+		{"mixed gate sets", []BatchCircuit{circuits[0], {Name: "native", Input: native}}},
+		{"openqasm", []BatchCircuit{{Name: "qasm", Input: NewQASMInput("OPENQASM 2.0;")}}},
+	} {
+		if _, err := NewBatchInput(tc.circuits...); !errors.Is(err, ErrInvalidCircuit) {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestValidateMultiCircuit(t *testing.T) {
+	input, err := NewBatchInput(testBatchCircuits(t)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input.Qubits = 1
+	if err := input.Validate(); !errors.Is(err, ErrInvalidCircuit) {
+		t.Fatalf("expected out of range qubit error, received %v", err)
+	}
+
+	input.Qubits = 2
+	input.Circuit = input.Circuits[0].Circuit
+	if err := input.Validate(); !errors.Is(err, ErrInvalidCircuit) {
+		t.Fatalf("expected circuit and circuits error, received %v", err)
+	}
+}
+
+func TestCreateBatchJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	circuits := testBatchCircuits(t)
+
+	input, err := NewBatchInput(circuits...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newGock().
+		Post(jobsPath).
+		JSON(&CreateJobRequest{Name: "sweep", Shots: 100, Target: "simulator", Input: input}).
+		Reply(200).
+		JSON(&CreateJobResponse{ID: "parent-id", Status: JobStatusReady})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	createJobWithStatus, err := client.CreateBatchJob(ctx, &CreateJobRequest{Name: "sweep", Shots: 100, Target: "simulator"}, circuits...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if createJobWithStatus.Response.ID != "parent-id" {
+		t.Fatalf("unexpected response: %+v", createJobWithStatus.Response)
+	}
+
+	if !gock.IsDone() {
+		t.Fatal("expected the batch job to be created")
+	}
+}
+
+func TestGetJobOutputs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/parent-id", jobsPath)).
+		Reply(200).
+		JSON(&GetJobResponse{ID: "parent-id", Status: JobStatusCompleted, Children: []string{"child-0", "child-1", "child-2"}})
+
+	children := map[string]struct {
+		name   string
+		output GetJobOutputResponse
+	}{
+		"child-0": {"theta-0", GetJobOutputResponse{"0": 1}},
+		"child-1": {"theta-1", GetJobOutputResponse{"0": 0.5, "2": 0.5}},
+		"child-2": {"theta-1", GetJobOutputResponse{"0": 0.25, "2": 0.75}},
+	}
+	for id, child := range children {
+		newGock().
+			Get(fmt.Sprintf("%s/%s", jobsPath, id)).
+			Reply(200).
+			JSON(&GetJobResponse{ID: id, Name: child.name, Status: JobStatusCompleted})
+
+		newGock().
+			Get(fmt.Sprintf("%s/%s/results", jobsPath, id)).
+			Reply(200).
+			JSON(child.output)
+	}
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	outputs, err := client.GetJobOutputs(ctx, "parent-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the second child named theta-1 is keyed by its ID
+	expected := map[string]GetJobOutputResponse{
+		"theta-0": children["child-0"].output,
+		"theta-1": children["child-1"].output,
+		"child-2": children["child-2"].output,
+	}
+
+	if diff := deep.Equal(expected, outputs); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestGetJobOutputsFailedChild(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/parent-id", jobsPath)).
+		Reply(200).
+		JSON(&GetJobResponse{ID: "parent-id", Status: JobStatusFailed, Children: []string{"child-0"}})
+
+	newGock().
+		Get(fmt.Sprintf("%s/child-0", jobsPath)).
+		Reply(200).
+		JSON(&GetJobResponse{ID: "child-0", Status: JobStatusFailed})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	if _, err := client.GetJobOutputs(ctx, "parent-id"); !errors.Is(err, ErrJobFailed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package ionq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is matched by the *BudgetError returned when a job is
+// refused by the budget set with WithBudget.
+var ErrBudgetExceeded = errors.New("ionq: budget exceeded")
+
+// BudgetError is returned by CreateJob when the estimated cost of a job is
+// above the per-job budget, or would bring the day's spend above the per-day
+// budget. The job is not submitted.
+type BudgetError struct {
+	Estimate *Estimate
+
+	// Limit is the budget that would be exceeded, PerJob or PerDay
+	Limit float64
+
+	// Spent is the spend recorded for the day, 0 for the per-job budget
+	Spent float64
+
+	PerDay bool
+}
+
+func (e *BudgetError) Error() string {
+	if e.PerDay {
+		return fmt.Sprintf("ionq: job estimated at $%.2f exceeds the daily budget of $%.2f, $%.2f already spent", e.Estimate.CostUSD, e.Limit, e.Spent)
+	}
+	return fmt.Sprintf("ionq: job estimated at $%.2f exceeds the per-job budget of $%.2f", e.Estimate.CostUSD, e.Limit)
+}
+
+// Is reports whether target is ErrBudgetExceeded.
+func (e *BudgetError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// SpendTracker records the estimated cost of the jobs submitted by a Client
+// to enforce a per-day budget.
+type SpendTracker interface {
+	// SpentToday returns the estimated cost of the jobs recorded today.
+	SpentToday(ctx context.Context) (float64, error)
+
+	// RecordSpend records the estimated cost of a submitted job.
+	RecordSpend(ctx context.Context, jobID string, estimate *Estimate) error
+}
+
+// Budget limits the estimated cost of the jobs a Client submits, in USD. A
+// zero limit is not enforced.
+type Budget struct {
+	PerJob float64
+	PerDay float64
+
+	// Estimator estimates the jobs, the zero Estimator when nil
+	Estimator *Estimator
+
+	// Tracker records the spend of the day. When nil, the spend is kept in
+	// memory by the Client, starting at 0 and resetting at midnight UTC.
+	Tracker SpendTracker
+}
+
+// WithBudget refuses to submit jobs whose estimated cost is above the budget,
+// returning a *BudgetError from CreateJob and CreateBatchJob. Jobs that
+// cannot be estimated are refused as well.
+//
+// Submissions are serialized while a per-day budget is set so concurrent
+// jobs cannot exceed it together.
+func WithBudget(budget Budget) Option {
+	return func(cfg *clientConfig) {
+		// each Client gets its own tracker, even when the option is reused
+		b := budget
+		if b.Estimator == nil {
+			b.Estimator = &Estimator{}
+		}
+		if b.Tracker == nil {
+			b.Tracker = &memorySpendTracker{now: time.Now}
+		}
+		cfg.budget = &b
+	}
+}
+
+// checkBudget returns the estimate of the job, or a *BudgetError if it is
+// above the budget.
+func (c *Client) checkBudget(ctx context.Context, createJobRequest *CreateJobRequest) (*Estimate, error) {
+	estimate, err := c.budget.Estimator.Estimate(createJobRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.budget.PerJob > 0 && estimate.CostUSD > c.budget.PerJob {
+		return nil, &BudgetError{Estimate: estimate, Limit: c.budget.PerJob}
+	}
+
+	if c.budget.PerDay > 0 {
+		spent, err := c.budget.Tracker.SpentToday(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if spent+estimate.CostUSD > c.budget.PerDay {
+			return nil, &BudgetError{Estimate: estimate, Limit: c.budget.PerDay, Spent: spent, PerDay: true}
+		}
+	}
+
+	return estimate, nil
+}
+
+// memorySpendTracker is the SpendTracker used when Budget.Tracker is nil.
+type memorySpendTracker struct {
+	now func() time.Time
+
+	mu    sync.Mutex
+	day   string
+	spent float64
+}
+
+func (t *memorySpendTracker) today() string {
+	return t.now().UTC().Format(time.DateOnly)
+}
+
+func (t *memorySpendTracker) SpentToday(context.Context) (float64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.day != t.today() {
+		return 0, nil
+	}
+	return t.spent, nil
+}
+
+func (t *memorySpendTracker) RecordSpend(_ context.Context, _ string, estimate *Estimate) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if today := t.today(); t.day != today {
+		t.day = today
+		t.spent = 0
+	}
+	t.spent += estimate.CostUSD
+	return nil
+}
@@ -0,0 +1,147 @@
+package ionq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+)
+
+func testBudgetRequest(t *testing.T) *CreateJobRequest {
+	t.Helper()
+
+	input, err := NewCircuit(2).H(0).CNOT(0, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &CreateJobRequest{Target: TargetAria1, Shots: 1000, Input: input}
+}
+
+var testBudgetEstimator = &Estimator{
+	Pricing: PricingTable{TargetAria1: {MinimumPerCircuit: 10}},
+}
+
+func TestBudgetPerJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithBudget(Budget{PerJob: 5, Estimator: testBudgetEstimator}))
+
+	_, err := client.CreateJob(ctx, testBudgetRequest(t))
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var budgetErr *BudgetError
+	if !errors.As(err, &budgetErr) || budgetErr.Limit != 5 || budgetErr.Estimate.CostUSD != 10 || budgetErr.PerDay {
+		t.Fatalf("unexpected budget error: %+v", budgetErr)
+	}
+
+	if gock.HasUnmatchedRequest() {
+		t.Fatal("expected the job not to be submitted")
+	}
+}
+
+func TestBudgetPerDay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	for range 2 {
+		newGock().
+			Post(jobsPath).
+			Reply(200).
+			JSON(&CreateJobResponse{ID: "some-id", Status: JobStatusReady})
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := &memorySpendTracker{now: func() time.Time { return now }}
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithBudget(Budget{PerDay: 25, Estimator: testBudgetEstimator, Tracker: tracker}))
+
+	for range 2 {
+		if _, err := client.CreateJob(ctx, testBudgetRequest(t)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := client.CreateJob(ctx, testBudgetRequest(t))
+
+	var budgetErr *BudgetError
+	if !errors.As(err, &budgetErr) || !budgetErr.PerDay || budgetErr.Spent != 20 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the spend resets the next day
+	now = now.Add(24 * time.Hour)
+
+	newGock().
+		Post(jobsPath).
+		Reply(200).
+		JSON(&CreateJobResponse{ID: "some-id", Status: JobStatusReady})
+
+	if _, err := client.CreateJob(ctx, testBudgetRequest(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !gock.IsDone() {
+		t.Fatal("expected every job within the budget to be submitted")
+	}
+}
+
+func TestBudgetFailedSubmissionNotRecorded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Post(jobsPath).
+		Reply(400).
+		JSON(map[string]any{"error": map[string]string{"type": "InvalidRequest", "message": "bad"}})
+
+	tracker := &memorySpendTracker{now: time.Now}
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithBudget(Budget{PerDay: 25, Estimator: testBudgetEstimator, Tracker: tracker}))
+
+	if _, err := client.CreateJob(ctx, testBudgetRequest(t)); !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if spent, _ := tracker.SpentToday(ctx); spent != 0 {
+		t.Fatalf("unexpected spend: %f", spent)
+	}
+}
+
+func TestBudgetOptionReused(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	for range 2 {
+		newGock().
+			Post(jobsPath).
+			Reply(200).
+			JSON(&CreateJobResponse{ID: "some-id", Status: JobStatusReady})
+	}
+
+	budget := WithBudget(Budget{PerDay: 15, Estimator: testBudgetEstimator})
+
+	// each client tracks its own spend
+	for range 2 {
+		client := NewClient(myFakeEndpoint, myFakeAPIKey, budget)
+		if _, err := client.CreateJob(ctx, testBudgetRequest(t)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !gock.IsDone() {
+		t.Fatal("expected every job within the budget to be submitted")
+	}
+}
@@ -0,0 +1,337 @@
+package ionq
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+const (
+	// CircuitFormat is the format of circuits built with NewCircuit.
+	CircuitFormat = "ionq.circuit.v0"
+
+	// FormatOpenQASM is the format of inputs holding an OpenQASM program in
+	// their Data field.
+	FormatOpenQASM = "openqasm"
+
+	// GatesetQIS is the abstract gate set, with rotations in radians.
+	GatesetQIS = "qis"
+)
+
+// Gates of the QIS gate set.
+const (
+	GateX    = "x"
+	GateY    = "y"
+	GateZ    = "z"
+	GateH    = "h"
+	GateS    = "s"
+	GateSi   = "si"
+	GateT    = "t"
+	GateTi   = "ti"
+	GateV    = "v"
+	GateVi   = "vi"
+	GateRX   = "rx"
+	GateRY   = "ry"
+	GateRZ   = "rz"
+	GateNot  = "not"
+	GateCNOT = "cnot"
+	GateSwap = "swap"
+)
+
+// ErrInvalidCircuit is wrapped by the errors returned when a circuit is
+// invalid.
+var ErrInvalidCircuit = errors.New("ionq: invalid circuit")
+
+var (
+	singleQubitGates = []string{GateX, GateY, GateZ, GateH, GateS, GateSi, GateT, GateTi, GateV, GateVi, GateNot, GateCNOT}
+	rotationGates    = []string{GateRX, GateRY, GateRZ}
+)
+
+// Circuit builds the JobInput of a QIS circuit. Every method returns the
+// Circuit so calls can be chained; the first invalid gate is reported by
+// Build and later gates are ignored.
+//
+// IonQ measures every qubit at the end of the circuit, so there is no
+// measurement gate.
+type Circuit struct {
+	gateList
+}
+
+// NewCircuit returns a Circuit acting on the given number of qubits.
+func NewCircuit(qubits uint) *Circuit {
+	return &Circuit{newGateList(qubits)}
+}
+
+// gateList holds the state shared by the circuit builders.
+type gateList struct {
+	qubits uint
+	gates  []CircuitInput
+	err    error
+}
+
+func newGateList(qubits uint) gateList {
+	l := gateList{qubits: qubits}
+	if qubits == 0 {
+		l.err = fmt.Errorf("%w: circuit must have at least one qubit", ErrInvalidCircuit)
+	}
+	return l
+}
+
+func (l *gateList) add(gate CircuitInput, validate func(CircuitInput, uint) error) {
+	if l.err != nil {
+		return
+	}
+
+	if err := validate(gate, l.qubits); err != nil {
+		l.err = fmt.Errorf("gate %d: %w", len(l.gates), err)
+		return
+	}
+
+	l.gates = append(l.gates, gate)
+}
+
+func (l *gateList) build(gateset string) (JobInput, error) {
+	if l.err != nil {
+		return JobInput{}, l.err
+	}
+
+	return JobInput{
+		Format:  CircuitFormat,
+		Gateset: gateset,
+		Qubits:  l.qubits,
+		Circuit: slices.Clone(l.gates),
+	}, nil
+}
+
+// Err returns the first error encountered while building the circuit.
+func (l *gateList) Err() error {
+	return l.err
+}
+
+func qubitRef(q uint) *uint {
+	return &q
+}
+
+// Gate appends a raw gate to the circuit after validating it.
+func (c *Circuit) Gate(gate CircuitInput) *Circuit {
+	c.add(gate, validateQISGate)
+	return c
+}
+
+func (c *Circuit) single(gate string, target uint) *Circuit {
+	return c.Gate(CircuitInput{Gate: gate, Target: qubitRef(target)})
+}
+
+func (c *Circuit) rotation(gate string, target uint, theta float64) *Circuit {
+	return c.Gate(CircuitInput{Gate: gate, Target: qubitRef(target), Rotation: theta})
+}
+
+// X applies a Pauli X gate.
+func (c *Circuit) X(target uint) *Circuit { return c.single(GateX, target) }
+
+// Y applies a Pauli Y gate.
+func (c *Circuit) Y(target uint) *Circuit { return c.single(GateY, target) }
+
+// Z applies a Pauli Z gate.
+func (c *Circuit) Z(target uint) *Circuit { return c.single(GateZ, target) }
+
+// H applies a Hadamard gate.
+func (c *Circuit) H(target uint) *Circuit { return c.single(GateH, target) }
+
+// S applies an S gate.
+func (c *Circuit) S(target uint) *Circuit { return c.single(GateS, target) }
+
+// Si applies the conjugate transpose of the S gate.
+func (c *Circuit) Si(target uint) *Circuit { return c.single(GateSi, target) }
+
+// T applies a T gate.
+func (c *Circuit) T(target uint) *Circuit { return c.single(GateT, target) }
+
+// Ti applies the conjugate transpose of the T gate.
+func (c *Circuit) Ti(target uint) *Circuit { return c.single(GateTi, target) }
+
+// V applies a V (square root of X) gate.
+func (c *Circuit) V(target uint) *Circuit { return c.single(GateV, target) }
+
+// Vi applies the conjugate transpose of the V gate.
+func (c *Circuit) Vi(target uint) *Circuit { return c.single(GateVi, target) }
+
+// RX applies a rotation of theta radians around the X axis.
+func (c *Circuit) RX(target uint, theta float64) *Circuit { return c.rotation(GateRX, target, theta) }
+
+// RY applies a rotation of theta radians around the Y axis.
+func (c *Circuit) RY(target uint, theta float64) *Circuit { return c.rotation(GateRY, target, theta) }
+
+// RZ applies a rotation of theta radians around the Z axis.
+func (c *Circuit) RZ(target uint, theta float64) *Circuit { return c.rotation(GateRZ, target, theta) }
+
+// CNOT applies a controlled X gate.
+func (c *Circuit) CNOT(control, target uint) *Circuit {
+	return c.Gate(CircuitInput{Gate: GateCNOT, Control: qubitRef(control), Target: qubitRef(target)})
+}
+
+// Toffoli applies an X gate controlled by two qubits.
+func (c *Circuit) Toffoli(control1, control2, target uint) *Circuit {
+	return c.Controlled(GateX, []uint{control1, control2}, target)
+}
+
+// Swap swaps the state of two qubits.
+func (c *Circuit) Swap(a, b uint) *Circuit {
+	return c.Gate(CircuitInput{Gate: GateSwap, Targets: []uint{a, b}})
+}
+
+// Controlled applies a single qubit gate to target controlled by every qubit
+// in controls.
+func (c *Circuit) Controlled(gate string, controls []uint, target uint) *Circuit {
+	return c.Gate(CircuitInput{Gate: gate, Controls: slices.Clone(controls), Target: qubitRef(target)})
+}
+
+// ControlledRotation applies a rotation gate of theta radians to target
+// controlled by every qubit in controls.
+func (c *Circuit) ControlledRotation(gate string, controls []uint, target uint, theta float64) *Circuit {
+	return c.Gate(CircuitInput{Gate: gate, Controls: slices.Clone(controls), Target: qubitRef(target), Rotation: theta})
+}
+
+// Build returns the JobInput of the circuit, or the first error encountered
+// while building it.
+func (c *Circuit) Build() (JobInput, error) {
+	return c.build(GatesetQIS)
+}
+
+func validateQubit(q uint, qubits uint) error {
+	if q >= qubits {
+		return fmt.Errorf("%w: qubit %d out of range for %d qubits", ErrInvalidCircuit, q, qubits)
+	}
+	return nil
+}
+
+// Qubits returns every qubit the gate acts on, controls first.
+func (c CircuitInput) Qubits() []uint {
+	var qubits []uint
+	if c.Control != nil {
+		qubits = append(qubits, *c.Control)
+	}
+	qubits = append(qubits, c.Controls...)
+	if c.Target != nil {
+		qubits = append(qubits, *c.Target)
+	}
+	qubits = append(qubits, c.Targets...)
+	return qubits
+}
+
+func validateGateQubits(gate CircuitInput, qubits uint) error {
+	seen := make(map[uint]bool)
+	for _, q := range gate.Qubits() {
+		if err := validateQubit(q, qubits); err != nil {
+			return err
+		}
+		if seen[q] {
+			return fmt.Errorf("%w: qubit %d used more than once by %s", ErrInvalidCircuit, q, gate.Gate)
+		}
+		seen[q] = true
+	}
+	return nil
+}
+
+func validateQISGate(gate CircuitInput, qubits uint) error {
+	if err := validateGateQubits(gate, qubits); err != nil {
+		return err
+	}
+
+	if gate.Phase != nil || len(gate.Phases) > 0 || gate.Angle != nil {
+		return fmt.Errorf("%w: %s cannot have native gate parameters", ErrInvalidCircuit, gate.Gate)
+	}
+
+	hasControls := gate.Control != nil || len(gate.Controls) > 0
+	if gate.Control != nil && len(gate.Controls) > 0 {
+		return fmt.Errorf("%w: %s cannot set both control and controls", ErrInvalidCircuit, gate.Gate)
+	}
+
+	switch {
+	case slices.Contains(singleQubitGates, gate.Gate):
+		if gate.Target == nil || len(gate.Targets) > 0 {
+			return fmt.Errorf("%w: %s requires a single target", ErrInvalidCircuit, gate.Gate)
+		}
+		if gate.Gate == GateCNOT && !hasControls {
+			return fmt.Errorf("%w: cnot requires a control", ErrInvalidCircuit)
+		}
+	case slices.Contains(rotationGates, gate.Gate):
+		if gate.Target == nil || len(gate.Targets) > 0 {
+			return fmt.Errorf("%w: %s requires a single target", ErrInvalidCircuit, gate.Gate)
+		}
+		if math.IsNaN(gate.Rotation) || math.IsInf(gate.Rotation, 0) {
+			return fmt.Errorf("%w: %s rotation must be finite", ErrInvalidCircuit, gate.Gate)
+		}
+	case gate.Gate == GateSwap:
+		if gate.Target != nil || len(gate.Targets) != 2 {
+			return fmt.Errorf("%w: swap requires two targets", ErrInvalidCircuit)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported gate %q", ErrInvalidCircuit, gate.Gate)
+	}
+
+	return nil
+}
+
+// Validate checks that every gate of the circuit, or of each circuit of a
+// multicircuit input, is valid for the input's gate set and acts on qubits
+// within the register. OpenQASM programs are only checked for being present.
+func (in JobInput) Validate() error {
+	if in.Format == FormatOpenQASM {
+		if in.Data == "" || len(in.Circuit) > 0 || len(in.Circuits) > 0 {
+			return fmt.Errorf("%w: openqasm input requires data and no circuit", ErrInvalidCircuit)
+		}
+		return nil
+	}
+
+	if in.Format != "" && in.Format != CircuitFormat {
+		return fmt.Errorf("%w: unsupported format %q", ErrInvalidCircuit, in.Format)
+	}
+
+	if in.Qubits == 0 {
+		return fmt.Errorf("%w: circuit must have at least one qubit", ErrInvalidCircuit)
+	}
+
+	var validate func(CircuitInput, uint) error
+	switch in.Gateset {
+	case "", GatesetQIS:
+		validate = validateQISGate
+	case GatesetNative:
+		validate = validateNativeGate
+	default:
+		return fmt.Errorf("%w: unsupported gateset %q", ErrInvalidCircuit, in.Gateset)
+	}
+
+	if len(in.Circuits) > 0 {
+		if len(in.Circuit) > 0 {
+			return fmt.Errorf("%w: input cannot set both circuit and circuits", ErrInvalidCircuit)
+		}
+
+		for i, circuit := range in.Circuits {
+			if err := validateGates(circuit.Circuit, in.Qubits, validate); err != nil {
+				return fmt.Errorf("circuit %d (%s): %w", i, circuit.Name, err)
+			}
+		}
+		return nil
+	}
+
+	return validateGates(in.Circuit, in.Qubits, validate)
+}
+
+func validateGates(gates []CircuitInput, qubits uint, validate func(CircuitInput, uint) error) error {
+	for i, gate := range gates {
+		if err := validate(gate, qubits); err != nil {
+			return fmt.Errorf("gate %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// NewQASMInput returns a JobInput submitting an OpenQASM program as is.
+func NewQASMInput(program string) JobInput {
+	return JobInput{
+		Format: FormatOpenQASM,
+		Data:   program,
+	}
+}
@@ -0,0 +1,144 @@
+package ionq
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestCircuitBuild(t *testing.T) {
+	input, err := NewCircuit(3).
+		H(0).
+		CNOT(0, 1).
+		RZ(2, math.Pi/4).
+		Toffoli(0, 1, 2).
+		Swap(1, 2).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if input.Qubits != 3 || input.Format != CircuitFormat || input.Gateset != GatesetQIS {
+		t.Fatalf("unexpected input: %+v", input)
+	}
+
+	body, err := json.Marshal(input.Circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[` +
+		`{"gate":"h","target":0},` +
+		`{"gate":"cnot","target":1,"control":0},` +
+		`{"gate":"rz","target":2,"rotation":0.7853981633974483},` +
+		`{"gate":"x","target":2,"controls":[0,1]},` +
+		`{"gate":"swap","targets":[1,2]}` +
+		`]`
+	if string(body) != expected {
+		t.Fatalf("unexpected circuit:\n%s\nexpected:\n%s", body, expected)
+	}
+}
+
+func TestCircuitZeroRotation(t *testing.T) {
+	input, err := NewCircuit(1).RZ(0, 0).H(0).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := json.Marshal(input.Circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{"gate":"rz","target":0,"rotation":0},{"gate":"h","target":0}]`
+	if string(body) != expected {
+		t.Fatalf("unexpected circuit:\n%s\nexpected:\n%s", body, expected)
+	}
+}
+
+func TestCircuitAllGates(t *testing.T) {
+	c := NewCircuit(2).
+		X(0).Y(0).Z(0).H(0).S(0).Si(0).T(0).Ti(0).V(0).Vi(0).
+		RX(1, 0.1).RY(1, 0.2).RZ(1, 0.3).
+		ControlledRotation(GateRX, []uint{0}, 1, math.Pi)
+
+	input, err := c.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(input.Circuit) != 14 {
+		t.Fatalf("unexpected number of gates: %d", len(input.Circuit))
+	}
+}
+
+func TestCircuitInputQubits(t *testing.T) {
+	for _, tc := range []struct {
+		gate     CircuitInput
+		expected []uint
+	}{
+		{CircuitInput{Gate: GateH, Target: qubitRef(2)}, []uint{2}},
+		{CircuitInput{Gate: GateCNOT, Control: qubitRef(1), Target: qubitRef(0)}, []uint{1, 0}},
+		{CircuitInput{Gate: GateX, Controls: []uint{3, 0}, Target: qubitRef(1)}, []uint{3, 0, 1}},
+		{CircuitInput{Gate: GateSwap, Control: qubitRef(2), Targets: []uint{0, 1}}, []uint{2, 0, 1}},
+	} {
+		if diff := deep.Equal(tc.expected, tc.gate.Qubits()); len(diff) > 0 {
+			t.Fatalf("%s: unexpected diff: %s", tc.gate.Gate, diff)
+		}
+	}
+}
+
+func TestCircuitValidation(t *testing.T) {
+	for name, c := range map[string]*Circuit{
+		"no qubits":         NewCircuit(0).H(0),
+		"target range":      NewCircuit(2).H(2),
+		"control range":     NewCircuit(2).CNOT(3, 0),
+		"same qubit":        NewCircuit(2).CNOT(1, 1),
+		"swap same qubit":   NewCircuit(2).Swap(0, 0),
+		"unknown gate":      NewCircuit(2).Gate(CircuitInput{Gate: "u3", Target: qubitRef(0)}),
+		"nan rotation":      NewCircuit(2).RX(0, math.NaN()),
+		"controls in range": NewCircuit(3).Controlled(GateZ, []uint{0, 5}, 1),
+		"cnot no control":   NewCircuit(2).Gate(CircuitInput{Gate: GateCNOT, Target: qubitRef(0)}),
+	} {
+		if _, err := c.Build(); !errors.Is(err, ErrInvalidCircuit) {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
+
+func TestCircuitKeepsFirstError(t *testing.T) {
+	c := NewCircuit(1).H(4).X(0)
+
+	if c.Err() == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(c.gates) != 0 {
+		t.Fatalf("expected gates after the error to be ignored")
+	}
+}
+
+func TestJobInputValidate(t *testing.T) {
+	input, err := NewCircuit(2).H(0).CNOT(0, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := input.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	input.Gateset = GatesetNative
+	if err := input.Validate(); !errors.Is(err, ErrInvalidCircuit) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input.Gateset = GatesetQIS
+	input.Qubits = 1
+	if err := input.Validate(); !errors.Is(err, ErrInvalidCircuit) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -2,23 +2,182 @@ package ionq
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"sync"
+	"time"
 )
 
+// DefaultEndpoint is the IonQ API endpoint used when no endpoint is given.
+const DefaultEndpoint = "https://api.ionq.co/v0.3"
+
+const defaultUserAgent = "ionq-go"
+
 type Client struct {
-	endpoint string // example: https://api.ionq.co/v0.3
-	apiKey   string
-	client   *http.Client
+	endpoint  string // example: https://api.ionq.co/v0.3
+	apiKey    string
+	userAgent string
+	client    *http.Client
+	retry     RetryPolicy
+
+	budget   *Budget
+	budgetMu sync.Mutex
 }
 
-func NewClient(endpoint string, apiKey string) *Client {
+// NewClient creates a Client for the given endpoint and API key. An empty
+// endpoint defaults to DefaultEndpoint. Options are applied in order.
+func NewClient(endpoint string, apiKey string, opts ...Option) *Client {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+
+	var cfg clientConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.endpoint != "" {
+		endpoint = cfg.endpoint
+	}
+
+	userAgent := defaultUserAgent
+	if cfg.userAgent != "" {
+		userAgent = cfg.userAgent
+	}
+
 	return &Client{
-		endpoint: endpoint,
-		apiKey:   apiKey,
-		client:   &http.Client{},
+		endpoint:  endpoint,
+		apiKey:    apiKey,
+		userAgent: userAgent,
+		client:    cfg.httpClient(),
+		retry:     cfg.retry,
+		budget:    cfg.budget,
 	}
 }
 
+// clientConfig collects the values set by the Options before the Client is
+// built, so the order of WithHTTPClient, WithTimeout and WithTransport does
+// not matter.
+type clientConfig struct {
+	endpoint   string
+	userAgent  string
+	client     *http.Client
+	timeout    time.Duration
+	transport  http.RoundTripper
+	hasTimeout bool
+	retry      RetryPolicy
+	budget     *Budget
+}
+
+// httpClient returns the http.Client to use. A client given with
+// WithHTTPClient is copied rather than modified when a timeout or transport
+// is also set.
+func (cfg *clientConfig) httpClient() *http.Client {
+	client := &http.Client{}
+	if cfg.client != nil {
+		if cfg.transport == nil && !cfg.hasTimeout {
+			return cfg.client
+		}
+		copied := *cfg.client
+		client = &copied
+	}
+
+	if cfg.transport != nil {
+		client.Transport = cfg.transport
+	}
+
+	if cfg.hasTimeout {
+		client.Timeout = cfg.timeout
+	}
+
+	return client
+}
+
 func (c *Client) makeURL(path string) string {
 	return fmt.Sprintf("%s/%s", c.endpoint, path)
 }
+
+// do sends the request and returns the response body and status code. A
+// non-2xx response is returned as an *APIError. Transient failures are retried
+// according to the Client's RetryPolicy.
+func (c *Client) do(req *http.Request) ([]byte, int, error) {
+	return doWithRetry(c, req, c.doOnce)
+}
+
+// doStream is do returning the body of a successful response unread, so it
+// can be decoded as it arrives. The caller must close it.
+func (c *Client) doStream(req *http.Request) (io.ReadCloser, int, error) {
+	return doWithRetry(c, req, c.doOnceStream)
+}
+
+func doWithRetry[T any](c *Client, req *http.Request, once func(*http.Request) (T, int, http.Header, error)) (T, int, error) {
+	for attempt := 1; ; attempt++ {
+		body, status, header, err := once(req)
+		if err == nil || attempt >= c.retry.MaxAttempts || !c.retry.shouldRetry(req, status, err) {
+			return body, status, err
+		}
+
+		ctx := req.Context()
+		wait := c.retry.backoff(attempt, header)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			// the next attempt could not finish before the deadline
+			return body, status, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return body, status, err
+		case <-timer.C:
+		}
+
+		retryReq := req.Clone(ctx)
+		if req.GetBody != nil {
+			retryReq.Body, err = req.GetBody()
+			if err != nil {
+				var zero T
+				return zero, 0, err
+			}
+		}
+		req = retryReq
+	}
+}
+
+func (c *Client) doOnce(req *http.Request) ([]byte, int, http.Header, error) {
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, res.StatusCode, res.Header, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return body, res.StatusCode, res.Header, newAPIError(res, body)
+	}
+
+	return body, res.StatusCode, res.Header, nil
+}
+
+func (c *Client) doOnceStream(req *http.Request) (io.ReadCloser, int, http.Header, error) {
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, res.StatusCode, res.Header, err
+		}
+		return nil, res.StatusCode, res.Header, newAPIError(res, body)
+	}
+
+	return res.Body, res.StatusCode, res.Header, nil
+}
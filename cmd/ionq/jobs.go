@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"ionq"
+)
+
+// jobCommand runs a "jobs" subcommand with its arguments.
+type jobCommand func(ctx context.Context, client *ionq.Client, args []string, e env) error
+
+var jobCommands = map[string]jobCommand{
+	"list":    listJobs,
+	"get":     getJob,
+	"results": getJobResults,
+	"cancel":  cancelJob,
+	"delete":  deleteJobs,
+	"submit":  submitJob,
+	"wait":    waitForJob,
+}
+
+// outputFormat is the value of the -o flag.
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+)
+
+func (f *outputFormat) String() string { return string(*f) }
+
+func (f *outputFormat) Set(value string) error {
+	switch outputFormat(value) {
+	case outputTable, outputJSON:
+		*f = outputFormat(value)
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, use table or json", value)
+}
+
+// newFlagSet returns the flags of a subcommand, taking the given positional
+// arguments.
+func newFlagSet(name, arguments string, e env) *flag.FlagSet {
+	flags := flag.NewFlagSet("ionq jobs "+name, flag.ContinueOnError)
+	flags.SetOutput(e.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: ionq jobs %s [flags] %s\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+func outputFlag(flags *flag.FlagSet) *outputFormat {
+	format := outputTable
+	flags.Var(&format, "o", "output `format`, table or json")
+	return &format
+}
+
+// parseArgs parses the flags and checks the number of positional arguments,
+// between min and max, or at least min when max is negative.
+func parseArgs(flags *flag.FlagSet, args []string, min, max int) error {
+	if err := flags.Parse(args); err != nil {
+		return usageError(err)
+	}
+
+	if n := flags.NArg(); n < min || (max >= 0 && n > max) {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func formatUnix(seconds int) string {
+	if seconds == 0 {
+		return "-"
+	}
+	return time.Unix(int64(seconds), 0).UTC().Format(time.RFC3339)
+}
+
+func writeJobs(w io.Writer, jobs []ionq.Job) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tSTATUS\tTARGET\tQUBITS\tSHOTS\tSUBMITTED\tCOST")
+	for _, job := range jobs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%d\t%s\t$%.2f\n",
+			job.ID, job.Name, job.Status, job.Target, job.Qubits, job.Shots, formatUnix(job.Request), job.CostUsd)
+	}
+	return tw.Flush()
+}
+
+func listJobs(ctx context.Context, client *ionq.Client, args []string, e env) error {
+	flags := newFlagSet("list", "", e)
+	status := flags.String("status", "", "only list jobs with this `status`")
+	limit := flags.Uint("limit", 25, "number of jobs per page")
+	next := flags.String("next", "", "`cursor` of the page to list, printed after each page")
+	all := flags.Bool("all", false, "list every page")
+	format := outputFlag(flags)
+	if err := parseArgs(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	filter := &ionq.GetJobsRequest{Status: ionq.JobStatus(*status), Limit: *limit, Next: *next}
+
+	var response ionq.GetJobsResponse
+	if *all {
+		jobs, err := client.CollectJobs(ctx, filter, 0)
+		if err != nil {
+			return err
+		}
+		response.Jobs = jobs
+	} else {
+		jobsResponseWithStatus, err := client.GetJobs(ctx, filter)
+		if err != nil {
+			return err
+		}
+		response = jobsResponseWithStatus.Response
+	}
+
+	if *format == outputJSON {
+		return writeJSON(e.stdout, response)
+	}
+
+	if err := writeJobs(e.stdout, response.Jobs); err != nil {
+		return err
+	}
+
+	if response.Next != "" {
+		fmt.Fprintf(e.stderr, "more jobs: ionq jobs list -next %s\n", response.Next)
+	}
+	return nil
+}
+
+func getJob(ctx context.Context, client *ionq.Client, args []string, e env) error {
+	flags := newFlagSet("get", "id", e)
+	format := outputFlag(flags)
+	if err := parseArgs(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	jobResponseWithStatus, err := client.GetJob(ctx, &ionq.GetJobRequest{ID: flags.Arg(0)})
+	if err != nil {
+		return err
+	}
+
+	job := ionq.Job(jobResponseWithStatus.Response)
+	if *format == outputJSON {
+		return writeJSON(e.stdout, job)
+	}
+	return writeJobs(e.stdout, []ionq.Job{job})
+}
+
+func getJobResults(ctx context.Context, client *ionq.Client, args []string, e env) error {
+	flags := newFlagSet("results", "id", e)
+	sharpen := flags.Bool("sharpen", false, "aggregate a debiased job by plurality vote")
+	precision := flags.Uint("precision", 0, "decimal places of the probabilities, the API default when 0")
+	format := outputFlag(flags)
+	if err := parseArgs(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	outputResponseWithStatus, err := client.GetJobOutput(ctx, &ionq.GetJobOutputRequest{
+		ID:        flags.Arg(0),
+		Sharpen:   *sharpen,
+		Precision: *precision,
+	})
+	if err != nil {
+		return err
+	}
+
+	output := outputResponseWithStatus.Response
+	if *format == outputJSON {
+		return writeJSON(e.stdout, output)
+	}
+
+	states := make([]string, 0, len(output))
+	for state := range output {
+		states = append(states, state)
+	}
+	slices.SortFunc(states, func(a, b string) int {
+		// states are decimal indices, shorter ones are smaller
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return strings.Compare(a, b)
+	})
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATE\tPROBABILITY")
+	for _, state := range states {
+		fmt.Fprintf(tw, "%s\t%s\n", state, strconv.FormatFloat(float64(output[state]), 'g', -1, 32))
+	}
+	return tw.Flush()
+}
+
+func cancelJob(ctx context.Context, client *ionq.Client, args []string, e env) error {
+	flags := newFlagSet("cancel", "id", e)
+	if err := parseArgs(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	cancelResponseWithStatus, err := client.CancelJob(ctx, &ionq.CancelJobRequest{ID: flags.Arg(0)})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stdout, "%s %s\n", cancelResponseWithStatus.Response.ID, cancelResponseWithStatus.Response.Status)
+	return nil
+}
+
+func deleteJobs(ctx context.Context, client *ionq.Client, args []string, e env) error {
+	flags := newFlagSet("delete", "id...", e)
+	if err := parseArgs(flags, args, 1, -1); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 1 {
+		deleteResponseWithStatus, err := client.DeleteJob(ctx, &ionq.DeleteJobRequest{ID: flags.Arg(0)})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(e.stdout, "%s %s\n", deleteResponseWithStatus.Response.ID, deleteResponseWithStatus.Response.Status)
+		return nil
+	}
+
+	deleteManyResponseWithStatus, err := client.DeleteManyJobs(ctx, &ionq.DeleteManyJobsRequest{IDs: flags.Args()})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range deleteManyResponseWithStatus.Response.IDS {
+		fmt.Fprintf(e.stdout, "%s %s\n", id, deleteManyResponseWithStatus.Response.Status)
+	}
+	return nil
+}
+
+// readJobFile reads the job of an OpenQASM program or a JSON file holding
+// either a CreateJobRequest or a JobInput.
+func readJobFile(path string) (*ionq.CreateJobRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if filepath.Ext(path) == ".qasm" || bytes.HasPrefix(bytes.TrimSpace(data), []byte("OPENQASM")) {
+		return &ionq.CreateJobRequest{Input: ionq.NewQASMInput(string(data))}, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var req ionq.CreateJobRequest
+	if _, ok := fields["input"]; ok {
+		err = json.Unmarshal(data, &req)
+	} else {
+		err = json.Unmarshal(data, &req.Input)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return &req, nil
+}
+
+func submitJob(ctx context.Context, client *ionq.Client, args []string, e env) error {
+	flags := newFlagSet("submit", "", e)
+	file := flags.String("f", "", "`file` holding the circuit, a JSON job or input, or an OpenQASM program")
+	name := flags.String("name", "", "job name")
+	target := flags.String("target", "", "`target` backend, simulator by default")
+	shots := flags.Uint("shots", 0, "number of shots")
+	noise := flags.String("noise", "", "noise `model` of the simulator")
+	wait := flags.Bool("wait", false, "wait for the job to finish")
+	noValidate := flags.Bool("no-validate", false, "send the job without checking it, for targets newer than this tool")
+	if err := parseArgs(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		fmt.Fprintln(e.stderr, "ionq: -f is required")
+		flags.Usage()
+		return errUsage
+	}
+
+	req, err := readJobFile(*file)
+	if err != nil {
+		return err
+	}
+
+	// flags override the values of the file
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			req.Name = *name
+		case "target":
+			req.Target = ionq.Target(*target)
+		case "shots":
+			req.Shots = *shots
+		case "noise":
+			if req.Noise == nil {
+				req.Noise = &ionq.NoiseInput{}
+			}
+			req.Noise.Model = ionq.NoiseModel(*noise)
+		}
+	})
+
+	if !*noValidate {
+		err := req.Validate()
+		if errors.Is(err, ionq.ErrUnknownTarget) {
+			return fmt.Errorf("%w, use -no-validate to send it anyway", err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	createResponseWithStatus, err := client.CreateJob(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	id := createResponseWithStatus.Response.ID
+	if !*wait {
+		fmt.Fprintln(e.stdout, id)
+		return nil
+	}
+
+	job, err := client.WaitForJob(ctx, id, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stdout, "%s %s\n", job.ID, job.Status)
+	return nil
+}
+
+func waitForJob(ctx context.Context, client *ionq.Client, args []string, e env) error {
+	flags := newFlagSet("wait", "id", e)
+	interval := flags.Duration("interval", time.Second, "wait between the first polls")
+	timeout := flags.Duration("timeout", 0, "give up after this `duration`, never when 0")
+	if err := parseArgs(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	job, err := client.WaitForJob(ctx, flags.Arg(0), &ionq.WaitOptions{Interval: *interval})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stdout, "%s %s\n", job.ID, job.Status)
+	return nil
+}
@@ -0,0 +1,154 @@
+// Command ionq manages IonQ jobs from the command line.
+//
+// Usage:
+//
+//	ionq [-endpoint url] [-config file] jobs <command> [flags] [args]
+//
+// The commands are list, get, results, cancel, delete, submit and wait; run
+// "ionq jobs <command> -h" for their flags. The API key is read from the
+// IONQ_API_KEY environment variable, or else from the "api_key" field of the
+// JSON config file, by default ionq/config.json in the user config directory.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"ionq"
+)
+
+// apiKeyEnv is the environment variable holding the API key.
+const apiKeyEnv = "IONQ_API_KEY"
+
+// errUsage is returned for invalid command lines, after the usage has been
+// printed.
+var errUsage = errors.New("invalid usage")
+
+// config is the content of the config file.
+type config struct {
+	APIKey   string `json:"api_key,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "ionq", "config.json")
+}
+
+// loadConfig reads the config file at path. A missing file is only an error
+// when the path was given explicitly.
+func loadConfig(path string, explicit bool) (config, error) {
+	var cfg config
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// env holds what a command needs from its environment, so commands can be
+// run from tests.
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+
+	err := run(ctx, os.Args[1:], env{stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv})
+	stop()
+
+	switch {
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "ionq: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, e env) error {
+	flags := flag.NewFlagSet("ionq", flag.ContinueOnError)
+	flags.SetOutput(e.stderr)
+	endpoint := flags.String("endpoint", "", "IonQ API `url`, "+ionq.DefaultEndpoint+" by default")
+	configPath := flags.String("config", defaultConfigPath(), "config `file` holding the API key")
+	flags.Usage = func() {
+		fmt.Fprintln(e.stderr, "usage: ionq [flags] jobs list|get|results|cancel|delete|submit|wait [flags] [args]")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return usageError(err)
+	}
+
+	explicit := false
+	flags.Visit(func(f *flag.Flag) {
+		explicit = explicit || f.Name == "config"
+	})
+
+	if flags.NArg() < 2 || flags.Arg(0) != "jobs" {
+		flags.Usage()
+		return errUsage
+	}
+
+	cmd, ok := jobCommands[flags.Arg(1)]
+	if !ok {
+		fmt.Fprintf(e.stderr, "ionq: unknown command %q\n", flags.Arg(1))
+		flags.Usage()
+		return errUsage
+	}
+
+	cfg, err := loadConfig(*configPath, explicit)
+	if err != nil {
+		return err
+	}
+
+	apiKey := e.getenv(apiKeyEnv)
+	if apiKey == "" {
+		apiKey = cfg.APIKey
+	}
+	if apiKey == "" {
+		return fmt.Errorf("no API key, set %s or api_key in %s", apiKeyEnv, *configPath)
+	}
+
+	if *endpoint == "" {
+		*endpoint = cfg.Endpoint
+	}
+
+	client := ionq.NewClient(*endpoint, apiKey, ionq.WithRetryPolicy(ionq.DefaultRetryPolicy()))
+
+	return cmd(ctx, client, flags.Args()[2:], e)
+}
+
+// usageError returns errUsage for flag parsing errors, which the flag
+// package already printed, and nil when help was requested.
+func usageError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return errUsage
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+	"ionq/ionqtest"
+)
+
+type cli struct {
+	t        *testing.T
+	endpoint string
+	config   string
+	apiKey   string
+}
+
+func newCLI(t *testing.T, server *ionqtest.Server) *cli {
+	t.Helper()
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	return &cli{
+		t:        t,
+		endpoint: ts.URL,
+		config:   writeFile(t, "config.json", "{}"),
+		apiKey:   ionqtest.DefaultAPIKey,
+	}
+}
+
+// run runs the command line and returns its standard output and error.
+func (c *cli) run(args ...string) (string, string, error) {
+	c.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	e := env{
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(key string) string {
+			if key == apiKeyEnv {
+				return c.apiKey
+			}
+			return ""
+		},
+	}
+
+	args = append([]string{"-endpoint", c.endpoint, "-config", c.config}, args...)
+	err := run(ctx, args, e)
+	return stdout.String(), stderr.String(), err
+}
+
+func (c *cli) mustRun(args ...string) string {
+	c.t.Helper()
+
+	stdout, stderr, err := c.run(args...)
+	if err != nil {
+		c.t.Fatalf("%v: %s", err, stderr)
+	}
+	return stdout
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const bellJob = `{
+	"name": "bell",
+	"input": {
+		"gateset": "qis",
+		"qubits": 2,
+		"circuit": [{"gate": "h", "target": 0}, {"gate": "cnot", "control": 0, "target": 1}]
+	}
+}`
+
+func TestSubmitAndInspect(t *testing.T) {
+	c := newCLI(t, ionqtest.New())
+
+	out := c.mustRun("jobs", "submit", "-f", writeFile(t, "bell.json", bellJob), "-wait")
+	fields := strings.Fields(out)
+	if len(fields) != 2 || fields[1] != string(ionq.JobStatusCompleted) {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	id := fields[0]
+
+	var output ionq.GetJobOutputResponse
+	if err := json.Unmarshal([]byte(c.mustRun("jobs", "results", "-o", "json", id)), &output); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(ionq.GetJobOutputResponse{"0": 0.5, "3": 0.5}, output); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	table := c.mustRun("jobs", "results", id)
+	if !strings.HasPrefix(table, "STATE") || !strings.Contains(table, "3  ") {
+		t.Fatalf("unexpected table: %q", table)
+	}
+
+	var job ionq.Job
+	if err := json.Unmarshal([]byte(c.mustRun("jobs", "get", "-o", "json", id)), &job); err != nil {
+		t.Fatal(err)
+	}
+
+	if job.ID != id || job.Name != "bell" || job.Qubits != 2 {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+
+	if list := c.mustRun("jobs", "list"); !strings.Contains(list, id) || !strings.Contains(list, "bell") {
+		t.Fatalf("unexpected list: %q", list)
+	}
+}
+
+func TestSubmitQASMWithFlags(t *testing.T) {
+	server := ionqtest.New()
+	c := newCLI(t, server)
+
+	program := "OPENQASM 2.0;\ninclude \"qelib1.inc\";\nqreg q[1];\nx q[0];\n"
+	id := strings.TrimSpace(c.mustRun("jobs", "submit", "-f", writeFile(t, "x.qasm", program), "-name", "flip", "-shots", "50"))
+
+	jobs := server.Jobs()
+	if len(jobs) != 1 || jobs[0].ID != id || jobs[0].Name != "flip" || jobs[0].Shots != 50 {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+
+	if out := c.mustRun("jobs", "wait", "-interval", "1ms", id); out != id+" completed\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	c := newCLI(t, ionqtest.New())
+
+	path := writeFile(t, "bell.json", bellJob)
+	for range 3 {
+		c.mustRun("jobs", "submit", "-f", path)
+	}
+
+	stdout, stderr, err := c.run("jobs", "list", "-limit", "2", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var page ionq.GetJobsResponse
+	if err := json.Unmarshal([]byte(stdout), &page); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Jobs) != 2 || page.Next == "" || stderr != "" {
+		t.Fatalf("unexpected page: %+v, %q", page, stderr)
+	}
+
+	_, stderr, err = c.run("jobs", "list", "-limit", "2")
+	if err != nil || !strings.Contains(stderr, "-next "+page.Next) {
+		t.Fatalf("expected the next cursor, got %q (%v)", stderr, err)
+	}
+
+	rest := c.mustRun("jobs", "list", "-limit", "2", "-next", page.Next)
+	if lines := strings.Split(strings.TrimSpace(rest), "\n"); len(lines) != 2 {
+		t.Fatalf("unexpected list: %q", rest)
+	}
+
+	all := c.mustRun("jobs", "list", "-limit", "2", "-all")
+	if lines := strings.Split(strings.TrimSpace(all), "\n"); len(lines) != 4 {
+		t.Fatalf("unexpected list: %q", all)
+	}
+}
+
+func TestCancelAndDelete(t *testing.T) {
+	server := ionqtest.New(ionqtest.WithDelays(time.Hour, 0))
+	c := newCLI(t, server)
+
+	path := writeFile(t, "bell.json", bellJob)
+	var ids []string
+	for range 3 {
+		ids = append(ids, strings.TrimSpace(c.mustRun("jobs", "submit", "-f", path)))
+	}
+
+	if out := c.mustRun("jobs", "cancel", ids[0]); out != ids[0]+" canceled\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	if _, _, err := c.run("jobs", "wait", "-interval", "1ms", ids[0]); !errors.Is(err, ionq.ErrJobCanceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out := c.mustRun("jobs", "delete", ids[0]); out != ids[0]+" deleted\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	out := c.mustRun("jobs", "delete", ids[1], ids[2])
+	if out != ids[1]+" deleted\n"+ids[2]+" deleted\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	if jobs := server.Jobs(); len(jobs) != 0 {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+}
+
+func TestAPIKeyFromConfig(t *testing.T) {
+	c := newCLI(t, ionqtest.New())
+	c.apiKey = ""
+
+	if _, _, err := c.run("jobs", "list"); err == nil || !strings.Contains(err.Error(), apiKeyEnv) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.config = writeFile(t, "config.json", `{"api_key": "`+ionqtest.DefaultAPIKey+`"}`)
+	c.mustRun("jobs", "list")
+
+	c.config = filepath.Join(t.TempDir(), "missing.json")
+	if _, _, err := c.run("jobs", "list"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected an explicit config file to be required, got %v", err)
+	}
+}
+
+func TestSubmitUnknownTarget(t *testing.T) {
+	c := newCLI(t, ionqtest.New())
+	path := writeFile(t, "bell.json", bellJob)
+
+	if _, _, err := c.run("jobs", "submit", "-f", path, "-target", "qpu.future"); !errors.Is(err, ionq.ErrUnknownTarget) {
+		t.Fatalf("expected the target to be checked, got %v", err)
+	}
+
+	// the fake server knows no more targets than the package and rejects it
+	_, _, err := c.run("jobs", "submit", "-f", path, "-target", "qpu.future", "-no-validate")
+	var apiErr *ionq.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected the job to be sent, got %v", err)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	c := newCLI(t, ionqtest.New())
+
+	for _, args := range [][]string{
+		{},
+		{"jobs"},
+		{"jobs", "unknown"},
+		{"jobs", "get"},
+		{"jobs", "list", "-o", "yaml"},
+		{"jobs", "submit"},
+	} {
+		if _, _, err := c.run(args...); !errors.Is(err, errUsage) {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package ionq
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// requestIDHeader is the response header IonQ uses to identify a request,
+// useful when reporting problems to IonQ support.
+const requestIDHeader = "X-Request-Id"
+
+// Sentinel errors that can be matched against an *APIError with errors.Is.
+var (
+	ErrBadRequest   = errors.New("ionq: bad request")
+	ErrUnauthorized = errors.New("ionq: unauthorized")
+	ErrForbidden    = errors.New("ionq: forbidden")
+	ErrNotFound     = errors.New("ionq: not found")
+	ErrRateLimited  = errors.New("ionq: rate limited")
+	ErrServer       = errors.New("ionq: server error")
+)
+
+// APIError is returned by the Client methods when the IonQ API responds with
+// a non-2xx status code.
+type APIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+	Code       string
+	RequestID  string
+	Body       []byte
+}
+
+// apiErrorBody covers the error shapes returned by the IonQ API, where
+// "error" is either a short description or an object with the details.
+type apiErrorBody struct {
+	Error   json.RawMessage `json:"error,omitempty"`
+	Message string          `json:"message,omitempty"`
+	Code    string          `json:"code,omitempty"`
+}
+
+type apiErrorDetails struct {
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+func newAPIError(res *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		RequestID:  res.Header.Get(requestIDHeader),
+		Body:       body,
+	}
+
+	var errBody apiErrorBody
+	if err := json.Unmarshal(body, &errBody); err != nil {
+		// the body is not JSON, keep it raw for the caller
+		return apiErr
+	}
+
+	apiErr.Message = errBody.Message
+	apiErr.Code = errBody.Code
+
+	if len(errBody.Error) > 0 {
+		var details apiErrorDetails
+		var errType string
+		if err := json.Unmarshal(errBody.Error, &details); err == nil {
+			apiErr.Type = details.Type
+			if details.Message != "" {
+				apiErr.Message = details.Message
+			}
+			if details.Code != "" {
+				apiErr.Code = details.Code
+			}
+		} else if err := json.Unmarshal(errBody.Error, &errType); err == nil {
+			apiErr.Type = errType
+		}
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("ionq: unexpected status %d", e.StatusCode)
+	if e.Type != "" {
+		msg += fmt.Sprintf(" (%s)", e.Type)
+	}
+	if e.Message != "" {
+		msg += fmt.Sprintf(": %s", e.Message)
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" [request id %s]", e.RequestID)
+	}
+	return msg
+}
+
+// Is reports whether the APIError matches one of the sentinel errors based on
+// its status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package ionq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+)
+
+func assertAPIError(t *testing.T, err error, status int, sentinel error) *APIError {
+	t.Helper()
+
+	if err == nil {
+		t.Fatal("expected error, received nil")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, received %T: %s", err, err)
+	}
+
+	if apiErr.StatusCode != status {
+		t.Fatalf("unexpected status: %d", apiErr.StatusCode)
+	}
+
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("expected error to match %s", sentinel)
+	}
+
+	return apiErr
+}
+
+func TestAPIErrorParsesErrorObject(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(404).
+		SetHeader("X-Request-Id", "my-request-id").
+		JSON(map[string]any{
+			"error": map[string]string{
+				"type":    "NotFoundError",
+				"message": "job not found",
+				"code":    "not_found",
+			},
+		})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := client.GetJob(ctx, &GetJobRequest{
+		ID: "some-id",
+	})
+	apiErr := assertAPIError(t, err, http.StatusNotFound, ErrNotFound)
+
+	if apiErr.Type != "NotFoundError" {
+		t.Fatalf("unexpected type: %s", apiErr.Type)
+	}
+
+	if apiErr.Message != "job not found" {
+		t.Fatalf("unexpected message: %s", apiErr.Message)
+	}
+
+	if apiErr.Code != "not_found" {
+		t.Fatalf("unexpected code: %s", apiErr.Code)
+	}
+
+	if apiErr.RequestID != "my-request-id" {
+		t.Fatalf("unexpected request id: %s", apiErr.RequestID)
+	}
+
+	if errors.Is(err, ErrBadRequest) {
+		t.Fatal("404 should not match ErrBadRequest")
+	}
+}
+
+func TestAPIErrorParsesStatusCodeShape(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(jobsPath).
+		Reply(401).
+		JSON(map[string]any{
+			"statusCode": 401,
+			"error":      "Unauthorized",
+			"message":    "Invalid key provided",
+		})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := client.GetJobs(ctx, &GetJobsRequest{})
+	apiErr := assertAPIError(t, err, http.StatusUnauthorized, ErrUnauthorized)
+
+	if apiErr.Type != "Unauthorized" {
+		t.Fatalf("unexpected type: %s", apiErr.Type)
+	}
+
+	if apiErr.Message != "Invalid key provided" {
+		t.Fatalf("unexpected message: %s", apiErr.Message)
+	}
+}
+
+func TestAPIErrorNonJSONBody(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Put(fmt.Sprintf("%s/some-id/status/cancel", jobsPath)).
+		Reply(502).
+		BodyString("Bad Gateway")
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := client.CancelJob(ctx, &CancelJobRequest{
+		ID: "some-id",
+	})
+	apiErr := assertAPIError(t, err, http.StatusBadGateway, ErrServer)
+
+	if string(apiErr.Body) != "Bad Gateway" {
+		t.Fatalf("unexpected body: %s", apiErr.Body)
+	}
+}
+
+func TestAPIErrorRateLimited(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id/results", jobsPath)).
+		Reply(429).
+		JSON(map[string]string{"message": "slow down"})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := client.GetJobOutput(ctx, &GetJobOutputRequest{
+		ID: "some-id",
+	})
+	assertAPIError(t, err, http.StatusTooManyRequests, ErrRateLimited)
+}
@@ -0,0 +1,246 @@
+package ionq
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DefaultShots is the number of shots the API runs when a job does not set
+// them.
+const DefaultShots = 100
+
+// ErrEstimate is wrapped by the errors returned when a job cannot be
+// estimated.
+var ErrEstimate = errors.New("ionq: cannot estimate job")
+
+// GateCounts holds the number of one and two qubit gates of a circuit.
+type GateCounts struct {
+	OneQ int `json:"1q,omitempty"`
+	TwoQ int `json:"2q,omitempty"`
+}
+
+func (c GateCounts) add(other GateCounts) GateCounts {
+	return GateCounts{OneQ: c.OneQ + other.OneQ, TwoQ: c.TwoQ + other.TwoQ}
+}
+
+func (c GateCounts) times(n int) GateCounts {
+	return GateCounts{OneQ: c.OneQ * n, TwoQ: c.TwoQ * n}
+}
+
+// Price is the price of running jobs on a target, in USD.
+type Price struct {
+	OneQGateShot float64
+	TwoQGateShot float64
+
+	// MinimumPerCircuit is charged for circuits whose gates cost less, each
+	// circuit of a multicircuit job being charged on its own.
+	MinimumPerCircuit float64
+}
+
+// PricingTable maps targets to their price.
+type PricingTable map[Target]Price
+
+// DefaultPricing holds the public list prices at the time of writing. Set
+// Estimator.Pricing to the prices of your contract.
+var DefaultPricing = PricingTable{
+	TargetSimulator:        {},
+	TargetHarmony:          {OneQGateShot: 0.00003, TwoQGateShot: 0.0003, MinimumPerCircuit: 1},
+	TargetAria1:            {OneQGateShot: 0.0002205, TwoQGateShot: 0.00098, MinimumPerCircuit: 97.5},
+	TargetAria2:            {OneQGateShot: 0.0002205, TwoQGateShot: 0.00098, MinimumPerCircuit: 97.5},
+	TargetForte1:           {OneQGateShot: 0.0002205, TwoQGateShot: 0.00098, MinimumPerCircuit: 97.5},
+	TargetForteEnterprise1: {OneQGateShot: 0.0002205, TwoQGateShot: 0.00098, MinimumPerCircuit: 97.5},
+}
+
+// DefaultTimings holds typical gate and measurement durations of the QPUs,
+// in seconds. Timings of a Characterization can be used instead.
+var DefaultTimings = map[Target]Timing{
+	TargetHarmony:          {OneQ: 0.00001, TwoQ: 0.0002, Readout: 0.00013, Reset: 0.00002},
+	TargetAria1:            {OneQ: 0.000135, TwoQ: 0.0006, Readout: 0.0003, Reset: 0.00002},
+	TargetAria2:            {OneQ: 0.000135, TwoQ: 0.0006, Readout: 0.0003, Reset: 0.00002},
+	TargetForte1:           {OneQ: 0.00013, TwoQ: 0.00097, Readout: 0.00015, Reset: 0.00005},
+	TargetForteEnterprise1: {OneQ: 0.00013, TwoQ: 0.00097, Readout: 0.00015, Reset: 0.00005},
+}
+
+// Estimate is the estimated cost and runtime of a job.
+type Estimate struct {
+	Target   Target
+	Shots    uint
+	Circuits int
+
+	// GateCounts sums the gates of every circuit of the job
+	GateCounts GateCounts
+
+	CostUSD float64
+
+	// Runtime is the time spent running the shots on the target, without
+	// the time spent in the queue. It is 0 for the simulator.
+	Runtime time.Duration
+}
+
+// Estimator estimates the cost and runtime of jobs before they are
+// submitted. The zero value uses DefaultPricing and DefaultTimings.
+type Estimator struct {
+	Pricing PricingTable
+	Timings map[Target]Timing
+
+	// ParseQASM converts OpenQASM inputs into circuits so their gates can be
+	// counted, usually qasm.Parse. OpenQASM inputs cannot be estimated
+	// without it.
+	ParseQASM func(program string) (JobInput, error)
+}
+
+// Estimate returns the estimated cost and runtime of the job, counting its
+// gates like CountGates.
+func (e *Estimator) Estimate(req *CreateJobRequest) (*Estimate, error) {
+	target := req.Target
+	if target == "" {
+		target = TargetSimulator
+	}
+
+	pricing := e.Pricing
+	if pricing == nil {
+		pricing = DefaultPricing
+	}
+
+	price, ok := pricing[target]
+	if !ok {
+		return nil, fmt.Errorf("%w: no price for target %q", ErrEstimate, target)
+	}
+
+	timings := e.Timings
+	if timings == nil {
+		timings = DefaultTimings
+	}
+
+	shots := req.Shots
+	if shots == 0 {
+		shots = DefaultShots
+	}
+
+	input := req.Input
+	if input.Format == FormatOpenQASM {
+		if e.ParseQASM == nil {
+			return nil, fmt.Errorf("%w: openqasm input requires Estimator.ParseQASM", ErrEstimate)
+		}
+
+		var err error
+		if input, err = e.ParseQASM(input.Data); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrEstimate, err)
+		}
+	}
+
+	circuits := [][]CircuitInput{input.Circuit}
+	if len(input.Circuits) > 0 {
+		circuits = circuits[:0]
+		for _, circuit := range input.Circuits {
+			circuits = append(circuits, circuit.Circuit)
+		}
+	}
+
+	estimate := &Estimate{Target: target, Shots: shots, Circuits: len(circuits)}
+
+	timing := timings[target]
+	shotTime := timing.Readout + timing.Reset
+
+	for _, circuit := range circuits {
+		counts, err := countGates(circuit)
+		if err != nil {
+			return nil, err
+		}
+		estimate.GateCounts = estimate.GateCounts.add(counts)
+
+		cost := float64(shots) * (float64(counts.OneQ)*price.OneQGateShot + float64(counts.TwoQ)*price.TwoQGateShot)
+		estimate.CostUSD += max(cost, price.MinimumPerCircuit)
+
+		seconds := float64(shots) * (float64(counts.OneQ)*timing.OneQ + float64(counts.TwoQ)*timing.TwoQ + shotTime)
+		estimate.Runtime += time.Duration(seconds * float64(time.Second))
+	}
+
+	return estimate, nil
+}
+
+// CountGates returns the number of one and two qubit gates of the input,
+// summed over every circuit of a multicircuit input.
+//
+// Native gates are counted as is. QIS gates with controls are counted with
+// textbook decompositions into CNOTs, as an approximation of the compiled
+// circuit: one CNOT for a singly controlled Pauli gate, two CNOTs and two
+// rotations for other controlled gates, six CNOTs and nine single qubit
+// gates for each extra control of a multi-controlled gate, and three
+// controlled X for a swap.
+func CountGates(input JobInput) (GateCounts, error) {
+	if input.Format == FormatOpenQASM {
+		return GateCounts{}, fmt.Errorf("%w: cannot count the gates of an openqasm input", ErrEstimate)
+	}
+
+	counts, err := countGates(input.Circuit)
+	if err != nil {
+		return GateCounts{}, err
+	}
+
+	for _, circuit := range input.Circuits {
+		circuitCounts, err := countGates(circuit.Circuit)
+		if err != nil {
+			return GateCounts{}, err
+		}
+		counts = counts.add(circuitCounts)
+	}
+
+	return counts, nil
+}
+
+func countGates(circuit []CircuitInput) (GateCounts, error) {
+	var counts GateCounts
+	for i, gate := range circuit {
+		gateCounts, err := countGate(gate)
+		if err != nil {
+			return GateCounts{}, fmt.Errorf("gate %d: %w", i, err)
+		}
+		counts = counts.add(gateCounts)
+	}
+	return counts, nil
+}
+
+var paulis = []string{GateX, GateY, GateZ, GateNot, GateCNOT}
+
+// controlledX counts an X gate with the given number of controls.
+func controlledX(controls int) GateCounts {
+	switch controls {
+	case 0:
+		return GateCounts{OneQ: 1}
+	case 1:
+		return GateCounts{TwoQ: 1}
+	}
+	return GateCounts{OneQ: 9 * (controls - 1), TwoQ: 6 * (controls - 1)}
+}
+
+func countGate(gate CircuitInput) (GateCounts, error) {
+	switch gate.Gate {
+	case GateGPI, GateGPI2:
+		return GateCounts{OneQ: 1}, nil
+	case GateMS, GateZZ:
+		return GateCounts{TwoQ: 1}, nil
+	}
+
+	controls := len(gate.Controls)
+	if gate.Control != nil {
+		controls++
+	}
+
+	switch {
+	case gate.Gate == GateSwap:
+		// the middle controlled X carries the controls
+		return controlledX(1).times(2).add(controlledX(controls + 1)), nil
+	case slices.Contains(paulis, gate.Gate):
+		return controlledX(controls), nil
+	case slices.Contains(singleQubitGates, gate.Gate), slices.Contains(rotationGates, gate.Gate):
+		if controls == 0 {
+			return GateCounts{OneQ: 1}, nil
+		}
+		return controlledX(controls).times(2).add(GateCounts{OneQ: 2}), nil
+	}
+
+	return GateCounts{}, fmt.Errorf("%w: unsupported gate %q", ErrEstimate, gate.Gate)
+}
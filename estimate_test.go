@@ -0,0 +1,146 @@
+package ionq
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestCountGates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		build    func() (JobInput, error)
+		expected GateCounts
+	}{
+		{
+			"bell",
+			NewCircuit(2).H(0).CNOT(0, 1).Build,
+			GateCounts{OneQ: 1, TwoQ: 1},
+		},
+		{
+			"controlled",
+			NewCircuit(4).
+				Toffoli(0, 1, 2).
+				ControlledRotation(GateRZ, []uint{0}, 1, math.Pi).
+				Controlled(GateX, []uint{0, 1, 2}, 3).
+				Build,
+			GateCounts{OneQ: 9 + 2 + 18, TwoQ: 6 + 2 + 12},
+		},
+		{
+			"swap",
+			NewCircuit(2).Swap(0, 1).Build,
+			GateCounts{TwoQ: 3},
+		},
+		{
+			"native",
+			NewNativeCircuit(2).GPI(0, 0).GPI2(1, 0.25).MS(0, 1, 0, 0).ZZ(0, 1, 0.1).Build,
+			GateCounts{OneQ: 2, TwoQ: 2},
+		},
+	} {
+		input, err := tc.build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts, err := CountGates(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := deep.Equal(tc.expected, counts); len(diff) > 0 {
+			t.Fatalf("%s: unexpected diff: %s", tc.name, diff)
+		}
+	}
+}
+
+func TestCountGatesQASM(t *testing.T) {
+	if _, err := CountGates(NewQASMInput("OPENQASM 2.0;")); !errors.Is(err, ErrEstimate) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	input, err := NewCircuit(2).H(0).CNOT(0, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	estimator := Estimator{
+		Pricing: PricingTable{TargetAria1: {OneQGateShot: 0.001, TwoQGateShot: 0.01, MinimumPerCircuit: 5}},
+		Timings: map[Target]Timing{TargetAria1: {OneQ: 0.0001, TwoQ: 0.001, Readout: 0.0005, Reset: 0.0004}},
+	}
+
+	estimate, err := estimator.Estimate(&CreateJobRequest{Target: TargetAria1, Shots: 1000, Input: input})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if estimate.GateCounts != (GateCounts{OneQ: 1, TwoQ: 1}) || estimate.Circuits != 1 {
+		t.Fatalf("unexpected estimate: %+v", estimate)
+	}
+
+	if math.Abs(estimate.CostUSD-11) > 1e-9 {
+		t.Fatalf("unexpected cost: %f", estimate.CostUSD)
+	}
+
+	if estimate.Runtime != 2*time.Second {
+		t.Fatalf("unexpected runtime: %s", estimate.Runtime)
+	}
+
+	// the default 100 shots cost less than the minimum
+	estimate, err = estimator.Estimate(&CreateJobRequest{Target: TargetAria1, Input: input})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if estimate.Shots != DefaultShots || estimate.CostUSD != 5 {
+		t.Fatalf("unexpected estimate: %+v", estimate)
+	}
+}
+
+func TestEstimateBatch(t *testing.T) {
+	input, err := NewBatchInput(testBatchCircuits(t)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	estimate, err := (&Estimator{}).Estimate(&CreateJobRequest{Target: TargetHarmony, Shots: 100, Input: input})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each circuit is charged the minimum
+	if estimate.Circuits != 2 || estimate.CostUSD != 2*DefaultPricing[TargetHarmony].MinimumPerCircuit {
+		t.Fatalf("unexpected estimate: %+v", estimate)
+	}
+}
+
+func TestEstimateQASM(t *testing.T) {
+	estimator := Estimator{
+		ParseQASM: func(string) (JobInput, error) {
+			return NewCircuit(1).H(0).Build()
+		},
+	}
+
+	estimate, err := estimator.Estimate(&CreateJobRequest{Input: NewQASMInput("OPENQASM 2.0;")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if estimate.Target != TargetSimulator || estimate.CostUSD != 0 || estimate.GateCounts.OneQ != 1 {
+		t.Fatalf("unexpected estimate: %+v", estimate)
+	}
+
+	if _, err := (&Estimator{}).Estimate(&CreateJobRequest{Input: NewQASMInput("OPENQASM 2.0;")}); !errors.Is(err, ErrEstimate) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEstimateUnknownTarget(t *testing.T) {
+	if _, err := (&Estimator{}).Estimate(&CreateJobRequest{Target: "qpu.ariaa"}); !errors.Is(err, ErrEstimate) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
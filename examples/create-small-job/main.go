@@ -19,35 +19,25 @@ func main() {
 		os.Getenv("IONQ_API_KEY"),
 	)
 
-	target1 := uint(0)
-	target2 := uint(1)
-	target3 := uint(2)
-
-	response, err := client.CreateJob(ctx, &ionq.CreateJobRequest{
-		Input: ionq.JobInput{
-
-			Format: "ionq.circuit.v0",
-			Qubits: 3,
-			Circuit: []ionq.CircuitInput{
-				{
-					Gate:   "h",
-					Target: &target1,
-				},
-				{
-					Gate:   "h",
-					Target: &target2,
-				},
-				{
-					Gate:   "h",
-					Target: &target3,
-				},
-			},
-		},
-		Shots: 1000,
+	input, err := ionq.NewCircuit(3).H(0).H(1).H(2).Build()
+	if err != nil {
+		panic(fmt.Sprintf("error building circuit: %s", err))
+	}
+
+	request := &ionq.CreateJobRequest{
+		Input:  input,
+		Shots:  1000,
+		Target: ionq.TargetSimulator,
 		Noise: &ionq.NoiseInput{
-			Model: "ideal",
+			Model: ionq.NoiseModelIdeal,
 		},
-	})
+	}
+
+	if err := request.Validate(); err != nil {
+		panic(fmt.Sprintf("invalid job request: %s", err))
+	}
+
+	response, err := client.CreateJob(ctx, request)
 
 	fmt.Printf("received response: %v\n", response)
 
@@ -61,31 +51,15 @@ func main() {
 
 	jobId := response.Response.ID
 
-	for {
-		select {
-		case <-ctx.Done():
-			panic(ctx.Err().Error())
-		case <-time.After(5 * time.Second):
-			response, err := client.GetJob(ctx, &ionq.GetJobRequest{
-				ID: jobId,
-			})
-			if err != nil {
-				panic(fmt.Sprintf("error getting job: %s", err))
-			}
-
-			if response.Status != http.StatusOK {
-				panic(fmt.Sprintf("received unexpected http status code: %d", response.Status))
-			}
-
-			fmt.Printf("received job status of %s\n", response.Response.Status)
-
-			if response.Response.Status == "completed" {
-				break
-			}
-		}
-		break
+	job, err := client.WaitForJob(ctx, jobId, &ionq.WaitOptions{
+		Interval: 5 * time.Second,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("error waiting for job: %s", err))
 	}
 
+	fmt.Printf("received job status of %s\n", job.Status)
+
 	outputResponse, err := client.GetJobOutput(ctx, &ionq.GetJobOutputRequest{
 		ID: jobId,
 	})
@@ -93,15 +67,15 @@ func main() {
 		panic(fmt.Sprintf("error getting job: %s", err))
 	}
 
-	if response.Status != http.StatusOK {
-		panic(fmt.Sprintf("received unexpected http status code: %d", response.Status))
+	if outputResponse.Status != http.StatusOK {
+		panic(fmt.Sprintf("received unexpected http status code: %d", outputResponse.Status))
 	}
 
 	fmt.Printf("job output is %v\n", outputResponse.Response)
 
 	for i := range 8 {
 		if outputResponse.Response[fmt.Sprintf("%d", i)] != 0.125 {
-			panic(fmt.Sprintf("unexpected ideal response for %d: %d", i, outputResponse.Response[fmt.Sprintf("%d", i)]))
+			panic(fmt.Sprintf("unexpected ideal response for %d: %f", i, outputResponse.Response[fmt.Sprintf("%d", i)]))
 		}
 	}
 }
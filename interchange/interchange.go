@@ -0,0 +1,220 @@
+// Package interchange converts circuits exported by other toolchains, Qiskit
+// JSON and QIR text, into an ionq.JobInput.
+//
+// Gates are converted with the same mapping as the qasm package: standard
+// gates go to the QIS gate set with rotations in radians, while the gpi,
+// gpi2, ms and zz gates of qiskit-ionq go to the native gate set with their
+// parameters in turns.
+package interchange
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"ionq"
+	"ionq/qasm"
+)
+
+// ErrUnsupported is wrapped by the errors returned for instructions that
+// cannot be expressed as an IonQ circuit.
+var ErrUnsupported = errors.New("interchange: unsupported instruction")
+
+// Options configures the conversion of a circuit.
+type Options struct {
+	// QubitMap maps the qubits of the source circuit to IonQ qubits. Qubits
+	// missing from the map keep their index. Two qubits of the circuit cannot
+	// end up on the same IonQ qubit.
+	QubitMap map[uint]uint
+
+	// Compact renumbers the qubits used by the circuit, after QubitMap is
+	// applied, to 0..n-1 keeping their order, dropping unused qubits.
+	Compact bool
+
+	// NativeParamsInRadians converts the parameters of the native gates from
+	// radians to turns, for circuits exported with radian parameters.
+	NativeParamsInRadians bool
+}
+
+// Circuit is a converted circuit.
+type Circuit struct {
+	Name  string
+	Input ionq.JobInput
+
+	// Measurements maps each measured IonQ qubit to the classical bit it was
+	// measured into. IonQ measures every qubit at the end of the circuit, so
+	// this only tells how to read the results.
+	Measurements map[uint]uint
+}
+
+// instruction is a gate or measurement read from a source circuit, using the
+// gate names of the qasm package.
+type instruction struct {
+	name   string
+	params []float64
+	qubits []uint
+	clbits []uint
+
+	// position describes where the instruction comes from, for errors
+	position string
+}
+
+const (
+	instructionMeasure = "measure"
+	instructionBarrier = "barrier"
+)
+
+// ignoredInstructions have no effect on the results.
+var ignoredInstructions = []string{instructionBarrier, "id", "delay"}
+
+var nativeGates = []string{ionq.GateGPI, ionq.GateGPI2, ionq.GateMS, ionq.GateZZ}
+
+func (o *Options) mapQubit(q uint) uint {
+	if o != nil {
+		if mapped, ok := o.QubitMap[q]; ok {
+			return mapped
+		}
+	}
+	return q
+}
+
+// sources returns the source qubit each IonQ qubit of QubitMap is mapped
+// from, or an error when two source qubits are mapped to the same IonQ qubit.
+func (o *Options) sources() (map[uint]uint, error) {
+	sources := make(map[uint]uint)
+	if o == nil {
+		return sources, nil
+	}
+
+	// sorted so the error names the same qubits every time
+	for _, q := range slices.Sorted(maps.Keys(o.QubitMap)) {
+		mapped := o.QubitMap[q]
+		if other, ok := sources[mapped]; ok {
+			return nil, fmt.Errorf("qubits %d and %d are both mapped to qubit %d", other, q, mapped)
+		}
+		sources[mapped] = q
+	}
+	return sources, nil
+}
+
+// build converts the instructions of a circuit into a Circuit. qubits is the
+// size of the source register, 0 when unknown.
+func build(name string, qubits uint, instructions []instruction, opts *Options) (*Circuit, error) {
+	sources, err := opts.sources()
+	if err != nil {
+		return nil, err
+	}
+
+	// remap the qubits first so Compact sees the final indices
+	mapped := make([]instruction, len(instructions))
+	used := make(map[uint]bool)
+	for i, inst := range instructions {
+		mapped[i] = inst
+		mapped[i].qubits = make([]uint, len(inst.qubits))
+		for j, q := range inst.qubits {
+			m := opts.mapQubit(q)
+			if source, ok := sources[m]; ok && source != q {
+				// q is not in the map and keeps the index another qubit is mapped to
+				return nil, fmt.Errorf("%s: qubits %d and %d are both mapped to qubit %d", inst.position, source, q, m)
+			}
+			mapped[i].qubits[j] = m
+			used[m] = true
+		}
+	}
+
+	if opts != nil && opts.Compact {
+		order := make([]uint, 0, len(used))
+		for q := range used {
+			order = append(order, q)
+		}
+		slices.Sort(order)
+
+		compact := make(map[uint]uint, len(order))
+		for i, q := range order {
+			compact[q] = uint(i)
+		}
+
+		for i := range mapped {
+			for j, q := range mapped[i].qubits {
+				mapped[i].qubits[j] = compact[q]
+			}
+		}
+		qubits = uint(len(order))
+	} else {
+		for i := range qubits {
+			used[opts.mapQubit(i)] = true
+		}
+		qubits = 0
+		for q := range used {
+			qubits = max(qubits, q+1)
+		}
+	}
+
+	circuit := &Circuit{
+		Name: name,
+		Input: ionq.JobInput{
+			Format:  ionq.CircuitFormat,
+			Gateset: ionq.GatesetQIS,
+			Qubits:  qubits,
+		},
+		Measurements: make(map[uint]uint),
+	}
+
+	gateset := ""
+	for _, inst := range mapped {
+		if slices.Contains(ignoredInstructions, inst.name) {
+			continue
+		}
+
+		for _, q := range inst.qubits {
+			if _, measured := circuit.Measurements[q]; measured {
+				return nil, fmt.Errorf("%s: %w: %s on qubit %d after it was measured", inst.position, ErrUnsupported, inst.name, q)
+			}
+		}
+
+		if inst.name == instructionMeasure {
+			if len(inst.clbits) != len(inst.qubits) {
+				return nil, fmt.Errorf("%s: measure requires a classical bit for each qubit", inst.position)
+			}
+			for i, q := range inst.qubits {
+				circuit.Measurements[q] = inst.clbits[i]
+			}
+			continue
+		}
+
+		params := inst.params
+		if opts != nil && opts.NativeParamsInRadians && slices.Contains(nativeGates, inst.name) {
+			params = make([]float64, len(inst.params))
+			for i, param := range inst.params {
+				params[i] = param / (2 * math.Pi)
+			}
+		}
+
+		gates, instGateset, err := qasm.ConvertGate(inst.name, params, inst.qubits)
+		if errors.Is(err, qasm.ErrUnsupported) {
+			return nil, fmt.Errorf("%s: %w: %q", inst.position, ErrUnsupported, inst.name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", inst.position, err)
+		}
+
+		if gateset != "" && gateset != instGateset {
+			return nil, fmt.Errorf("%s: %w: %q mixes the %s and %s gate sets", inst.position, ErrUnsupported, inst.name, gateset, instGateset)
+		}
+		gateset = instGateset
+
+		circuit.Input.Circuit = append(circuit.Input.Circuit, gates...)
+	}
+
+	if gateset != "" {
+		circuit.Input.Gateset = gateset
+	}
+
+	if err := circuit.Input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return circuit, nil
+}
@@ -0,0 +1,190 @@
+package interchange
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	qirCall           = regexp.MustCompile(`\bcall\s+\w+\s+@__quantum__(qis|rt)__(\w+?)__(body|adj|ctl)\s*\((.*)\)`)
+	qirDefine         = regexp.MustCompile(`^\s*define\s+[^@]*@([\w.$]+)\s*\(`)
+	qirRequiredQubits = regexp.MustCompile(`"(?:required_num_qubits|num_required_qubits)"="(\d+)"`)
+	qirIntToPtr       = regexp.MustCompile(`^inttoptr\s*\(\s*i64\s+(\d+)\s+to\s+[^)]+\)$`)
+)
+
+// qirNames maps the QIS functions whose name differs from the qasm package.
+var qirNames = map[string]string{
+	"cnot":    "cx",
+	"s__adj":  "sdg",
+	"t__adj":  "tdg",
+	"mz":      instructionMeasure,
+	"m":       instructionMeasure,
+	"measure": instructionMeasure,
+}
+
+// FromQIR converts the text form of a QIR base profile program into a
+// Circuit named after its entry point. Qubits and results must be static, as
+// null or inttoptr constants, with either typed or opaque pointers.
+func FromQIR(text string, opts *Options) (*Circuit, error) {
+	var (
+		name         string
+		qubits       uint
+		instructions []instruction
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		src := scanner.Text()
+		if i := strings.IndexByte(src, ';'); i >= 0 {
+			src = src[:i]
+		}
+
+		if match := qirRequiredQubits.FindStringSubmatch(src); match != nil {
+			n, err := strconv.ParseUint(match[1], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("qir: line %d: %w", line, err)
+			}
+			qubits = uint(n)
+		}
+
+		if match := qirDefine.FindStringSubmatch(src); match != nil && name == "" {
+			name = match[1]
+		}
+
+		match := qirCall.FindStringSubmatch(src)
+		if match == nil || match[1] == "rt" {
+			continue
+		}
+
+		inst, err := qirInstruction(match[2], match[3], match[4])
+		if err != nil {
+			return nil, fmt.Errorf("qir: line %d: %w", line, err)
+		}
+		inst.position = fmt.Sprintf("qir: line %d", line)
+		instructions = append(instructions, inst)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("qir: %w", err)
+	}
+
+	if len(instructions) == 0 {
+		return nil, fmt.Errorf("qir: no quantum instructions found")
+	}
+
+	return build(name, qubits, instructions, opts)
+}
+
+func qirInstruction(function, variant, args string) (instruction, error) {
+	switch variant {
+	case "adj":
+		function += "__adj"
+	case "ctl":
+		return instruction{}, fmt.Errorf("%w: controlled %q", ErrUnsupported, function)
+	}
+
+	inst := instruction{name: function}
+	if mapped, ok := qirNames[function]; ok {
+		inst.name = mapped
+	}
+
+	for _, arg := range splitQIRArgs(args) {
+		typ, value, _ := strings.Cut(arg, " ")
+		value = strings.TrimSpace(value)
+
+		switch {
+		case typ == "double":
+			param, err := parseQIRDouble(value)
+			if err != nil {
+				return instruction{}, err
+			}
+			inst.params = append(inst.params, param)
+		case typ == "ptr" || strings.HasSuffix(typ, "*"):
+			index, err := parseQIRPointer(value)
+			if err != nil {
+				return instruction{}, err
+			}
+
+			// results follow the qubits, and are only taken by measurements
+			isResult := strings.HasPrefix(typ, "%Result")
+			if typ == "ptr" {
+				isResult = inst.name == instructionMeasure && len(inst.qubits) > 0
+			}
+			if isResult {
+				inst.clbits = append(inst.clbits, index)
+			} else {
+				inst.qubits = append(inst.qubits, index)
+			}
+		default:
+			return instruction{}, fmt.Errorf("%w: argument %q of %q", ErrUnsupported, arg, function)
+		}
+	}
+
+	return inst, nil
+}
+
+// splitQIRArgs splits a list of arguments on the commas outside of
+// parentheses.
+func splitQIRArgs(args string) []string {
+	var (
+		split []string
+		depth int
+		start int
+	)
+	for i, c := range args {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				split = append(split, strings.TrimSpace(args[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(args[start:]); last != "" {
+		split = append(split, last)
+	}
+	return split
+}
+
+// parseQIRDouble parses a double constant, either decimal or the
+// hexadecimal form LLVM uses for values without an exact decimal form.
+func parseQIRDouble(value string) (float64, error) {
+	if hex, ok := strings.CutPrefix(value, "0x"); ok {
+		bits, err := strconv.ParseUint(hex, 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid double %q", value)
+		}
+		return math.Float64frombits(bits), nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid double %q", value)
+	}
+	return f, nil
+}
+
+func parseQIRPointer(value string) (uint, error) {
+	if value == "null" {
+		return 0, nil
+	}
+
+	match := qirIntToPtr.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("%w: dynamic qubit or result %q", ErrUnsupported, value)
+	}
+
+	index, err := strconv.ParseUint(match[1], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid pointer %q", value)
+	}
+	return uint(index), nil
+}
@@ -0,0 +1,107 @@
+package interchange
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+func TestFromQIRTypedPointers(t *testing.T) {
+	circuit, err := FromQIR(`
+%Qubit = type opaque
+%Result = type opaque
+
+define void @bell() #0 {
+entry:
+  call void @__quantum__rt__initialize(i8* null)
+  call void @__quantum__qis__h__body(%Qubit* null)
+  call void @__quantum__qis__cnot__body(%Qubit* null, %Qubit* inttoptr (i64 1 to %Qubit*))
+  call void @__quantum__qis__rz__body(double 0x3FE921FB54442D18, %Qubit* inttoptr (i64 1 to %Qubit*))
+  call void @__quantum__qis__t__adj(%Qubit* null) ; tdg
+  call void @__quantum__qis__mz__body(%Qubit* null, %Result* inttoptr (i64 1 to %Result*))
+  call void @__quantum__qis__mz__body(%Qubit* inttoptr (i64 1 to %Qubit*), %Result* null)
+  call void @__quantum__rt__result_record_output(%Result* null, i8* null)
+  ret void
+}
+
+declare void @__quantum__qis__h__body(%Qubit*)
+
+attributes #0 = { "entry_point" "qir_profiles"="base_profile" "required_num_qubits"="3" "required_num_results"="2" }
+`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := ionq.NewCircuit(3).
+		H(0).
+		CNOT(0, 1).
+		RZ(1, math.Pi/4).
+		Ti(0).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Circuit{Name: "bell", Input: input, Measurements: map[uint]uint{0: 1, 1: 0}}
+
+	if diff := deep.Equal(expected, circuit); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestFromQIROpaquePointers(t *testing.T) {
+	circuit, err := FromQIR(`
+define void @main() #0 {
+  call void @__quantum__qis__rx__body(double 1.5, ptr inttoptr (i64 2 to ptr))
+  call void @__quantum__qis__cz__body(ptr null, ptr inttoptr (i64 2 to ptr))
+  call void @__quantum__qis__mz__body(ptr inttoptr (i64 2 to ptr), ptr inttoptr (i64 3 to ptr))
+  ret void
+}
+`, &Options{Compact: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	control, target := uint(0), uint(1)
+	input, err := ionq.NewCircuit(2).
+		RX(1, 1.5).
+		Gate(ionq.CircuitInput{Gate: ionq.GateZ, Control: &control, Target: &target}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Circuit{Name: "main", Input: input, Measurements: map[uint]uint{1: 3}}
+
+	if diff := deep.Equal(expected, circuit); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestFromQIRErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		text        string
+		unsupported bool
+	}{
+		{"reset", "call void @__quantum__qis__reset__body(ptr null)", true},
+		{"controlled", "call void @__quantum__qis__x__ctl(ptr null, ptr null)", true},
+		{"dynamic qubit", "call void @__quantum__qis__x__body(%Qubit* %q)", true},
+		{"gate after measure", "call void @__quantum__qis__mz__body(ptr null, ptr null)\ncall void @__quantum__qis__x__body(ptr null)", true},
+		{"double", "call void @__quantum__qis__rx__body(double pi, ptr null)", false},
+		{"empty", "define void @main() {\n  ret void\n}", false},
+	} {
+		_, err := FromQIR(tc.text, nil)
+		if err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+
+		if errors.Is(err, ErrUnsupported) != tc.unsupported {
+			t.Fatalf("%s: unexpected unsupported error: %s", tc.name, err)
+		}
+	}
+}
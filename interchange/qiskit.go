@@ -0,0 +1,109 @@
+package interchange
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// qiskitInstruction is an instruction of an assembled Qiskit experiment.
+type qiskitInstruction struct {
+	Name   string            `json:"name"`
+	Params []json.RawMessage `json:"params,omitempty"`
+	Qubits []uint            `json:"qubits,omitempty"`
+	Memory []uint            `json:"memory,omitempty"`
+	Clbits []uint            `json:"clbits,omitempty"`
+
+	Conditional *json.RawMessage `json:"conditional,omitempty"`
+	Condition   *json.RawMessage `json:"condition,omitempty"`
+}
+
+type qiskitExperiment struct {
+	Header struct {
+		Name    string `json:"name,omitempty"`
+		NQubits uint   `json:"n_qubits,omitempty"`
+	} `json:"header"`
+	Config struct {
+		NQubits uint `json:"n_qubits,omitempty"`
+	} `json:"config"`
+
+	// set by circuits serialized on their own rather than in a Qobj
+	Name      string `json:"name,omitempty"`
+	NumQubits uint   `json:"num_qubits,omitempty"`
+
+	Instructions []qiskitInstruction `json:"instructions"`
+}
+
+type qiskitQobj struct {
+	Experiments []qiskitExperiment `json:"experiments"`
+}
+
+// FromQiskitJSON converts the experiments of an assembled Qiskit Qobj, or a
+// single experiment, into Circuits named after the experiment headers.
+func FromQiskitJSON(data []byte, opts *Options) ([]*Circuit, error) {
+	var qobj qiskitQobj
+	if err := json.Unmarshal(data, &qobj); err != nil {
+		return nil, fmt.Errorf("qiskit: %w", err)
+	}
+
+	if len(qobj.Experiments) == 0 {
+		var experiment qiskitExperiment
+		if err := json.Unmarshal(data, &experiment); err != nil {
+			return nil, fmt.Errorf("qiskit: %w", err)
+		}
+		if experiment.Instructions == nil {
+			return nil, fmt.Errorf("qiskit: no experiments or instructions found")
+		}
+		qobj.Experiments = []qiskitExperiment{experiment}
+	}
+
+	circuits := make([]*Circuit, len(qobj.Experiments))
+	for i, experiment := range qobj.Experiments {
+		circuit, err := experiment.convert(i, opts)
+		if err != nil {
+			return nil, err
+		}
+		circuits[i] = circuit
+	}
+
+	return circuits, nil
+}
+
+func (e *qiskitExperiment) convert(index int, opts *Options) (*Circuit, error) {
+	name := e.Header.Name
+	if name == "" {
+		name = e.Name
+	}
+
+	qubits := max(e.Header.NQubits, e.Config.NQubits, e.NumQubits)
+
+	instructions := make([]instruction, len(e.Instructions))
+	for i, qiskitInst := range e.Instructions {
+		position := fmt.Sprintf("qiskit: experiment %d, instruction %d", index, i)
+
+		if qiskitInst.Conditional != nil || qiskitInst.Condition != nil {
+			return nil, fmt.Errorf("%s: %w: conditional %q", position, ErrUnsupported, qiskitInst.Name)
+		}
+
+		params := make([]float64, len(qiskitInst.Params))
+		for j, raw := range qiskitInst.Params {
+			if err := json.Unmarshal(raw, &params[j]); err != nil {
+				return nil, fmt.Errorf("%s: %w: parameter %s of %q is not a number", position, ErrUnsupported, raw, qiskitInst.Name)
+			}
+		}
+
+		clbits := qiskitInst.Memory
+		if clbits == nil {
+			clbits = qiskitInst.Clbits
+		}
+
+		instructions[i] = instruction{
+			name:     qiskitInst.Name,
+			params:   params,
+			qubits:   qiskitInst.Qubits,
+			clbits:   clbits,
+			position: position,
+		}
+	}
+
+	return build(name, qubits, instructions, opts)
+}
@@ -0,0 +1,160 @@
+package interchange
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+const bellQobj = `{
+	"qobj_id": "bell",
+	"type": "QASM",
+	"experiments": [
+		{
+			"header": {"name": "bell", "n_qubits": 2, "memory_slots": 2},
+			"config": {"n_qubits": 2, "memory_slots": 2},
+			"instructions": [
+				{"name": "h", "qubits": [0]},
+				{"name": "cx", "qubits": [0, 1]},
+				{"name": "barrier", "qubits": [0, 1]},
+				{"name": "measure", "qubits": [0], "memory": [1]},
+				{"name": "measure", "qubits": [1], "memory": [0]}
+			]
+		},
+		{
+			"header": {"name": "rotations", "n_qubits": 1},
+			"instructions": [
+				{"name": "rx", "params": [1.5707963267948966], "qubits": [0]},
+				{"name": "sdg", "qubits": [0]}
+			]
+		}
+	]
+}`
+
+func TestFromQiskitJSON(t *testing.T) {
+	circuits, err := FromQiskitJSON([]byte(bellQobj), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bell, err := ionq.NewCircuit(2).H(0).CNOT(0, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotations, err := ionq.NewCircuit(1).RX(0, math.Pi/2).Si(0).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*Circuit{
+		{Name: "bell", Input: bell, Measurements: map[uint]uint{0: 1, 1: 0}},
+		{Name: "rotations", Input: rotations, Measurements: map[uint]uint{}},
+	}
+
+	if diff := deep.Equal(expected, circuits); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestFromQiskitJSONSingleExperiment(t *testing.T) {
+	data := `{
+		"name": "native",
+		"num_qubits": 3,
+		"instructions": [
+			{"name": "gpi", "params": [3.141592653589793], "qubits": [2]},
+			{"name": "ms", "params": [0, 1.5707963267948966, 0.7853981633974483], "qubits": [2, 0]},
+			{"name": "measure", "qubits": [0, 2], "clbits": [0, 1]}
+		]
+	}`
+
+	circuits, err := FromQiskitJSON([]byte(data), &Options{
+		QubitMap:              map[uint]uint{2: 1},
+		Compact:               true,
+		NativeParamsInRadians: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := ionq.NewNativeCircuit(2).
+		GPI(1, 0.5).
+		PartialMS(1, 0, 0, 0.25, 0.125).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(circuits) != 1 || circuits[0].Name != "native" {
+		t.Fatalf("unexpected circuits: %+v", circuits)
+	}
+
+	if diff := deep.Equal(expected, circuits[0].Input); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if diff := deep.Equal(map[uint]uint{0: 0, 1: 1}, circuits[0].Measurements); len(diff) > 0 {
+		t.Fatalf("unexpected measurements diff: %s", diff)
+	}
+}
+
+func TestFromQiskitJSONQubitMap(t *testing.T) {
+	data := `{"header": {"n_qubits": 2}, "instructions": [{"name": "cx", "qubits": [0, 1]}]}`
+
+	circuits, err := FromQiskitJSON([]byte(data), &Options{QubitMap: map[uint]uint{0: 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := ionq.NewCircuit(5).CNOT(4, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expected, circuits[0].Input); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestFromQiskitJSONQubitMapCollision(t *testing.T) {
+	data := `{"header": {"n_qubits": 3}, "instructions": [{"name": "cx", "qubits": [0, 1]}, {"name": "h", "qubits": [2]}]}`
+
+	for _, qubitMap := range []map[uint]uint{
+		{0: 5, 1: 5},
+		{0: 2},
+	} {
+		if _, err := FromQiskitJSON([]byte(data), &Options{QubitMap: qubitMap}); err == nil {
+			t.Fatalf("%v: expected error", qubitMap)
+		}
+	}
+}
+
+func TestFromQiskitJSONErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		data        string
+		unsupported bool
+	}{
+		{"reset", `{"instructions": [{"name": "reset", "qubits": [0]}]}`, true},
+		{"conditional", `{"instructions": [{"name": "x", "qubits": [0], "conditional": 0}]}`, true},
+		{"unbound parameter", `{"instructions": [{"name": "rx", "params": ["theta"], "qubits": [0]}]}`, true},
+		{"gate after measure", `{"instructions": [{"name": "measure", "qubits": [0], "memory": [0]}, {"name": "x", "qubits": [0]}]}`, true},
+		{"mixed gate sets", `{"instructions": [{"name": "h", "qubits": [0]}, {"name": "gpi", "params": [0], "qubits": [0]}]}`, true},
+		{"qubits", `{"instructions": [{"name": "cx", "qubits": [0]}]}`, false},
+		{"empty", `{}`, false},
+		{"json", `[`, false},
+	} {
+		_, err := FromQiskitJSON([]byte(tc.data), nil)
+		if err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+
+		if errors.Is(err, ErrUnsupported) != tc.unsupported {
+			t.Fatalf("%s: unexpected unsupported error: %s", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package ionqtest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"ionq"
+	"ionq/qasm"
+)
+
+// job is a job held by the Server. Its status is derived from the time it
+// was created, so it never needs to be advanced.
+type job struct {
+	job      ionq.Job
+	created  time.Time
+	canceled time.Time
+	output   ionq.GetJobOutputResponse
+	failure  string
+	cost     float64
+}
+
+func newJobID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Jobs returns the jobs held by the Server as the API would return them now,
+// newest first.
+func (s *Server) Jobs() []ionq.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	jobs := make([]ionq.Job, 0, len(s.order))
+	for _, id := range slices.Backward(s.order) {
+		jobs = append(jobs, s.view(s.jobs[id], now))
+	}
+	return jobs
+}
+
+func (s *Server) status(j *job, now time.Time) ionq.JobStatus {
+	elapsed := now.Sub(j.created)
+	switch {
+	case !j.canceled.IsZero():
+		return ionq.JobStatusCanceled
+	case elapsed < s.queued:
+		return ionq.JobStatusSubmitted
+	case elapsed < s.queued+s.running:
+		return ionq.JobStatusRunning
+	case j.failure != "":
+		return ionq.JobStatusFailed
+	}
+	return ionq.JobStatusCompleted
+}
+
+// view returns the job as the API would return it at the given time.
+func (s *Server) view(j *job, now time.Time) ionq.Job {
+	v := j.job
+	v.Metadata = maps.Clone(j.job.Metadata)
+	v.Children = slices.Clone(j.job.Children)
+	v.Status = s.status(j, now)
+	v.Request = int(j.created.Unix())
+
+	switch v.Status {
+	case ionq.JobStatusRunning:
+		v.Start = int(j.created.Add(s.queued).Unix())
+	case ionq.JobStatusCompleted, ionq.JobStatusFailed:
+		v.Start = int(j.created.Add(s.queued).Unix())
+		v.Response = int(j.created.Add(s.queued + s.running).Unix())
+		v.ExecutionTime = int(s.running.Milliseconds())
+	}
+
+	if v.Status == ionq.JobStatusCompleted {
+		v.CostUsd = j.cost
+		v.ResultsURL = fmt.Sprintf("%s/jobs/%s/results", apiVersionPrefix, j.job.ID)
+	}
+
+	if v.Status == ionq.JobStatusFailed {
+		v.Failure.Error = j.failure
+		v.Failure.Code = "SimulationError"
+	}
+
+	return v
+}
+
+// newJob returns a job for the request, running its circuit right away.
+func (s *Server) newJob(req *ionq.CreateJobRequest, now time.Time) (*job, error) {
+	estimator := &ionq.Estimator{ParseQASM: qasm.Parse}
+	estimate, err := estimator.Estimate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &job{
+		job: ionq.Job{
+			ID:         newJobID(),
+			Name:       req.Name,
+			Target:     estimate.Target,
+			Metadata:   maps.Clone(req.Metadata),
+			Shots:      int(req.Shots),
+			GateCounts: estimate.GateCounts,
+			Qubits:     int(req.Input.Qubits),
+		},
+		created: now,
+		cost:    estimate.CostUSD,
+	}
+
+	if req.Noise != nil {
+		j.job.Noise.Model = req.Noise.Model
+		j.job.Noise.Seed = req.Noise.Seed
+	}
+	if req.ErrorMitigation != nil {
+		j.job.ErrorMitigation.Debias = req.ErrorMitigation.Debias
+	}
+
+	if len(req.Input.Circuits) == 0 {
+		j.output, j.failure = s.run(req)
+	}
+
+	return j, nil
+}
+
+// run simulates the request, returning why it failed instead of its output
+// when it cannot be simulated.
+func (s *Server) run(req *ionq.CreateJobRequest) (ionq.GetJobOutputResponse, string) {
+	r := *req
+	if r.Shots == 0 && r.Target.IsQPU() {
+		// QPUs always sample
+		r.Shots = ionq.DefaultShots
+	}
+
+	var output ionq.GetJobOutputResponse
+	var err error
+	if r.Shots > 0 {
+		output, err = s.simulator.Sample(&r)
+	} else {
+		output, err = s.simulator.Run(&r)
+	}
+
+	if err != nil {
+		return nil, err.Error()
+	}
+	return output, ""
+}
+
+// add stores a job created from the request, with a child job for each
+// circuit of a multicircuit input.
+func (s *Server) add(req *ionq.CreateJobRequest) (*job, error) {
+	now := s.now()
+
+	parent, err := s.newJob(req, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var children []*job
+	for _, circuit := range req.Input.Circuits {
+		childReq := *req
+		childReq.Name = circuit.Name
+		childReq.Input = ionq.JobInput{
+			Gateset: req.Input.Gateset,
+			Qubits:  req.Input.Qubits,
+			Circuit: circuit.Circuit,
+		}
+
+		child, err := s.newJob(&childReq, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if child.failure != "" && parent.failure == "" {
+			parent.failure = fmt.Sprintf("child job %s failed: %s", child.job.ID, child.failure)
+		}
+
+		parent.job.Children = append(parent.job.Children, child.job.ID)
+		children = append(children, child)
+	}
+
+	if len(children) > 0 {
+		parent.job.Circuits = len(children)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range append([]*job{parent}, children...) {
+		s.jobs[j.job.ID] = j
+		s.order = append(s.order, j.job.ID)
+	}
+
+	return parent, nil
+}
+
+func (s *Server) createJob(w http.ResponseWriter, r *http.Request) {
+	var req ionq.CreateJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job: %s", err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	j, err := s.add(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ionq.CreateJobResponse{ID: j.job.ID, Status: ionq.JobStatusSubmitted})
+}
+
+func (s *Server) getJobs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	status := ionq.JobStatus(q.Get("status"))
+	if status != "" {
+		if err := status.Validate(); err != nil {
+			writeError(w, http.StatusBadRequest, "%s", err)
+			return
+		}
+	}
+
+	limit := defaultPageSize
+	if l := q.Get("limit"); l != "" && l != "0" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit %q", l)
+			return
+		}
+		limit = n
+	}
+
+	var ids []string
+	for _, id := range q["id"] {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := q.Get("next")
+	if next != "" && s.jobs[next] == nil {
+		writeError(w, http.StatusBadRequest, "invalid next cursor %q", next)
+		return
+	}
+
+	now := s.now()
+	var matching []ionq.Job
+	started := next == ""
+	for _, id := range slices.Backward(s.order) {
+		if !started {
+			started = id == next
+			continue
+		}
+
+		if len(ids) > 0 && !slices.Contains(ids, id) {
+			continue
+		}
+
+		job := s.view(s.jobs[id], now)
+		if status != "" && job.Status != status {
+			continue
+		}
+
+		matching = append(matching, job)
+	}
+
+	var response ionq.GetJobsResponse
+	if len(matching) > limit {
+		response.Jobs = matching[:limit]
+		response.Next = matching[limit-1].ID
+	} else {
+		response.Jobs = matching
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// lookup returns the job of the request path, writing a not found error
+// when there is none. It must be called with s.mu held.
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request) *job {
+	j := s.jobs[r.PathValue("id")]
+	if j == nil {
+		writeError(w, http.StatusNotFound, "job %s not found", r.PathValue("id"))
+	}
+	return j
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.lookup(w, r)
+	if j == nil {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.view(j, s.now()))
+}
+
+func (s *Server) getJobOutput(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	// sharpening only changes debiased outputs, which the simulator never
+	// produces, so it is accepted and ignored
+	if sharpen := q.Get("sharpen"); sharpen != "" {
+		if _, err := strconv.ParseBool(sharpen); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid sharpen %q", sharpen)
+			return
+		}
+	}
+
+	precision := -1
+	if p := q.Get("precision"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid precision %q", p)
+			return
+		}
+		precision = n
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.lookup(w, r)
+	if j == nil {
+		return
+	}
+
+	if status := s.status(j, s.now()); status != ionq.JobStatusCompleted {
+		writeError(w, http.StatusBadRequest, "job %s is %s, results are only available once it completed", j.job.ID, status)
+		return
+	}
+
+	if len(j.job.Children) == 0 {
+		writeJSON(w, http.StatusOK, round(j.output, precision))
+		return
+	}
+
+	// the output of a multicircuit job holds the output of each child
+	outputs := make(map[string]ionq.GetJobOutputResponse, len(j.job.Children))
+	for _, id := range j.job.Children {
+		outputs[id] = round(s.jobs[id].output, precision)
+	}
+	writeJSON(w, http.StatusOK, outputs)
+}
+
+// round rounds the probabilities of output to the given number of decimal
+// places, leaving them as they are for a negative precision.
+func round(output ionq.GetJobOutputResponse, precision int) ionq.GetJobOutputResponse {
+	if precision < 0 {
+		return output
+	}
+
+	scale := math.Pow10(precision)
+	rounded := make(ionq.GetJobOutputResponse, len(output))
+	for state, p := range output {
+		rounded[state] = float32(math.Round(float64(p)*scale) / scale)
+	}
+	return rounded
+}
+
+func (s *Server) cancelJob(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.lookup(w, r)
+	if j == nil {
+		return
+	}
+
+	now := s.now()
+	if status := s.status(j, now); status.IsTerminal() {
+		writeError(w, http.StatusBadRequest, "job %s is already %s", j.job.ID, status)
+		return
+	}
+
+	j.canceled = now
+	for _, id := range j.job.Children {
+		s.jobs[id].canceled = now
+	}
+
+	writeJSON(w, http.StatusOK, ionq.CancelJobResponse{ID: j.job.ID, Status: ionq.JobStatusCanceled})
+}
+
+// remove deletes a job with its children. It must be called with s.mu held.
+func (s *Server) remove(id string) bool {
+	j := s.jobs[id]
+	if j == nil {
+		return false
+	}
+
+	for _, child := range j.job.Children {
+		s.remove(child)
+	}
+
+	delete(s.jobs, id)
+	s.order = slices.DeleteFunc(s.order, func(other string) bool { return other == id })
+	return true
+}
+
+func (s *Server) deleteJob(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.lookup(w, r)
+	if j == nil {
+		return
+	}
+
+	s.remove(j.job.ID)
+	writeJSON(w, http.StatusOK, ionq.DeleteJobResponse{ID: j.job.ID, Status: "deleted"})
+}
+
+func (s *Server) deleteManyJobs(w http.ResponseWriter, r *http.Request) {
+	var req ionq.DeleteManyJobsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request: %s", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := []string{}
+	for _, id := range req.IDs {
+		if s.remove(id) {
+			deleted = append(deleted, id)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, ionq.DeleteManyJobsResponse{IDS: deleted, Status: "deleted"})
+}
@@ -0,0 +1,224 @@
+// Package ionqtest provides an in-memory fake of the IonQ v0.3 jobs API for
+// integration tests.
+//
+// A Server is an http.Handler meant to be started with httptest.NewServer
+// and reached with ionq.WithBaseURL:
+//
+//	server := ionqtest.New()
+//	ts := httptest.NewServer(server)
+//	defer ts.Close()
+//
+//	client := ionq.NewClient("", ionqtest.DefaultAPIKey, ionq.WithBaseURL(ts.URL))
+//
+// Jobs are run with the sim package when they are created, and move from
+// submitted to running to completed over the delays set with WithDelays.
+// Requests can be made to fail with Server.Inject.
+package ionqtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"ionq/sim"
+)
+
+// DefaultAPIKey is the API key a Server accepts unless WithAPIKey is used.
+const DefaultAPIKey = "ionqtest-key"
+
+// defaultPageSize is the number of jobs listed when a request sets no limit.
+const defaultPageSize = 25
+
+// apiVersionPrefix is stripped from request paths, so the Server can be used
+// with either the bare test server URL or one ending in the API version.
+const apiVersionPrefix = "/v0.3"
+
+// Option configures a Server created with New.
+type Option func(*Server)
+
+// WithAPIKey sets the API key the Server accepts, DefaultAPIKey by default.
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithDelays sets how long jobs stay submitted, then running, before they
+// complete. Jobs complete immediately by default.
+func WithDelays(queued, running time.Duration) Option {
+	return func(s *Server) {
+		s.queued = queued
+		s.running = running
+	}
+}
+
+// WithSimulator sets the simulator running the jobs, a zero sim.Simulator by
+// default.
+func WithSimulator(simulator *sim.Simulator) Option {
+	return func(s *Server) {
+		s.simulator = simulator
+	}
+}
+
+// WithClock sets the function returning the current time, time.Now by
+// default, so tests can move jobs through their statuses without sleeping.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// Fault makes matching requests fail with an API error.
+type Fault struct {
+	// Method and Path select the requests to fail. Path is matched with
+	// path.Match against the request path without the API version, for
+	// example "/jobs/*/results". Empty values match every request.
+	Method string
+	Path   string
+
+	Status  int
+	Message string
+
+	// Times is the number of requests failed, 1 when zero.
+	Times int
+}
+
+func (f *Fault) matches(r *http.Request, p string) bool {
+	if f.Method != "" && f.Method != r.Method {
+		return false
+	}
+	if f.Path == "" {
+		return true
+	}
+	ok, _ := path.Match(f.Path, p)
+	return ok
+}
+
+// Server is an in-memory fake of the IonQ jobs API.
+type Server struct {
+	apiKey    string
+	queued    time.Duration
+	running   time.Duration
+	simulator *sim.Simulator
+	now       func() time.Time
+
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	jobs     map[string]*job
+	order    []string // job IDs in creation order
+	faults   []Fault
+	requests int
+}
+
+// New returns a Server without jobs.
+func New(opts ...Option) *Server {
+	s := &Server{
+		apiKey:    DefaultAPIKey,
+		simulator: &sim.Simulator{},
+		now:       time.Now,
+		jobs:      make(map[string]*job),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /jobs", s.createJob)
+	s.mux.HandleFunc("GET /jobs", s.getJobs)
+	s.mux.HandleFunc("DELETE /jobs", s.deleteManyJobs)
+	s.mux.HandleFunc("GET /jobs/{id}", s.getJob)
+	s.mux.HandleFunc("DELETE /jobs/{id}", s.deleteJob)
+	s.mux.HandleFunc("GET /jobs/{id}/results", s.getJobOutput)
+	s.mux.HandleFunc("PUT /jobs/{id}/status/cancel", s.cancelJob)
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "no route for %s %s", r.Method, r.URL.Path)
+	})
+
+	return s
+}
+
+// Inject adds faults failing the next matching requests. Faults are checked
+// in order, before the API key.
+func (s *Server) Inject(faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, faults...)
+}
+
+// Requests returns the number of requests the Server received.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := strings.TrimPrefix(r.URL.Path, apiVersionPrefix)
+
+	s.mu.Lock()
+	s.requests++
+	w.Header().Set("X-Request-Id", fmt.Sprintf("ionqtest-%d", s.requests))
+	fault := s.takeFault(r, p)
+	s.mu.Unlock()
+
+	if fault != nil {
+		writeError(w, fault.Status, "%s", fault.Message)
+		return
+	}
+
+	if r.Header.Get("Authorization") != "apiKey "+s.apiKey {
+		writeError(w, http.StatusUnauthorized, "invalid API key")
+		return
+	}
+
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = p
+	r2.URL.RawPath = ""
+	s.mux.ServeHTTP(w, r2)
+}
+
+// takeFault returns the first fault matching the request, consuming one of
+// its times. It must be called with s.mu held.
+func (s *Server) takeFault(r *http.Request, p string) *Fault {
+	for i := range s.faults {
+		fault := s.faults[i]
+		if !fault.matches(r, p) {
+			continue
+		}
+
+		if fault.Times <= 1 {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		} else {
+			s.faults[i].Times--
+		}
+		return &fault
+	}
+	return nil
+}
+
+// errorBody is the shape of the errors returned by the IonQ API.
+type errorBody struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, errorBody{
+		Error:   http.StatusText(status),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,341 @@
+package ionqtest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+// clock is a settable time source for WithClock.
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestClient(t *testing.T, server *Server) *ionq.Client {
+	t.Helper()
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+
+	return ionq.NewClient("", DefaultAPIKey, ionq.WithBaseURL(ts.URL+"/v0.3"))
+}
+
+func bellRequest(t *testing.T) *ionq.CreateJobRequest {
+	t.Helper()
+
+	input, err := ionq.NewCircuit(2).H(0).CNOT(0, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ionq.CreateJobRequest{Name: "bell", Target: ionq.TargetSimulator, Input: input}
+}
+
+func TestCreateJobAndGetOutput(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, New())
+
+	created, err := client.CreateJob(ctx, bellRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Response.Status != ionq.JobStatusSubmitted {
+		t.Fatalf("unexpected status: %s", created.Response.Status)
+	}
+
+	job, err := client.WaitForJob(ctx, created.Response.ID, &ionq.WaitOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if job.Name != "bell" || job.Qubits != 2 || job.GateCounts != (ionq.GateCounts{OneQ: 1, TwoQ: 1}) {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+
+	output, err := client.GetJobOutput(ctx, &ionq.GetJobOutputRequest{ID: job.ID, Precision: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(ionq.GetJobOutputResponse{"0": 0.5, "3": 0.5}, output.Response); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestStatusProgression(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c := &clock{now: time.Unix(1700000000, 0)}
+	client := newTestClient(t, New(WithDelays(time.Minute, time.Minute), WithClock(c.Now)))
+
+	created, err := client.CreateJob(ctx, bellRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := created.Response.ID
+
+	for _, expected := range []ionq.JobStatus{ionq.JobStatusSubmitted, ionq.JobStatusRunning, ionq.JobStatusCompleted} {
+		job, err := client.GetJob(ctx, &ionq.GetJobRequest{ID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if job.Response.Status != expected {
+			t.Fatalf("unexpected status: %s, expected %s", job.Response.Status, expected)
+		}
+
+		if expected != ionq.JobStatusCompleted {
+			_, err := client.GetJobOutput(ctx, &ionq.GetJobOutputRequest{ID: id})
+			if !errors.Is(err, ionq.ErrBadRequest) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		c.Advance(time.Minute)
+	}
+}
+
+func TestListJobsPagination(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := New()
+	client := newTestClient(t, server)
+
+	var ids []string
+	for range 5 {
+		created, err := client.CreateJob(ctx, bellRequest(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append([]string{created.Response.ID}, ids...)
+	}
+
+	page, err := client.GetJobs(ctx, &ionq.GetJobsRequest{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Response.Jobs) != 2 || page.Response.Next != ids[1] {
+		t.Fatalf("unexpected page: %+v", page.Response)
+	}
+
+	jobs, err := client.CollectJobs(ctx, &ionq.GetJobsRequest{Limit: 2}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var listed []string
+	for _, job := range jobs {
+		listed = append(listed, job.ID)
+	}
+
+	if diff := deep.Equal(ids, listed); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if _, err := client.CancelJob(ctx, &ionq.CancelJobRequest{ID: ids[0]}); !errors.Is(err, ionq.ErrBadRequest) {
+		t.Fatalf("expected completed jobs not to be canceled, got %v", err)
+	}
+
+	jobs, err = client.CollectJobs(ctx, &ionq.GetJobsRequest{IDs: ids[1:3], Status: ionq.JobStatusCompleted}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jobs) != 2 || jobs[0].ID != ids[1] || jobs[1].ID != ids[2] {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+}
+
+func TestCancelAndDeleteJobs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := New(WithDelays(time.Hour, 0))
+	client := newTestClient(t, server)
+
+	var ids []string
+	for range 3 {
+		created, err := client.CreateJob(ctx, bellRequest(t))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.Response.ID)
+	}
+
+	canceled, err := client.CancelJob(ctx, &ionq.CancelJobRequest{ID: ids[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if canceled.Response.Status != ionq.JobStatusCanceled {
+		t.Fatalf("unexpected status: %s", canceled.Response.Status)
+	}
+
+	if _, err := client.WaitForJob(ctx, ids[0], nil); !errors.Is(err, ionq.ErrJobCanceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.DeleteJob(ctx, &ionq.DeleteJobRequest{ID: ids[0]}); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := client.DeleteManyJobs(ctx, &ionq.DeleteManyJobsRequest{IDs: []string{ids[0], ids[1], ids[2]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(ids[1:], deleted.Response.IDS); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if _, err := client.GetJob(ctx, &ionq.GetJobRequest{ID: ids[1]}); !errors.Is(err, ionq.ErrNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if jobs := server.Jobs(); len(jobs) != 0 {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ts := httptest.NewServer(New(WithAPIKey("right")))
+	defer ts.Close()
+
+	client := ionq.NewClient("", "wrong", ionq.WithBaseURL(ts.URL))
+	if _, err := client.GetJobs(ctx, &ionq.GetJobsRequest{}); !errors.Is(err, ionq.ErrUnauthorized) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client = ionq.NewClient("", "right", ionq.WithBaseURL(ts.URL))
+	if _, err := client.GetJobs(ctx, &ionq.GetJobsRequest{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInjectFault(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := New()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	client := ionq.NewClient("", DefaultAPIKey, ionq.WithBaseURL(ts.URL))
+
+	server.Inject(Fault{Method: http.MethodPost, Path: "/jobs", Status: http.StatusServiceUnavailable, Message: "down for maintenance", Times: 2})
+
+	for range 2 {
+		_, err := client.CreateJob(ctx, bellRequest(t))
+
+		var apiErr *ionq.APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, ionq.ErrServer) || apiErr.Message != "down for maintenance" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if apiErr.RequestID == "" {
+			t.Fatal("expected a request id")
+		}
+	}
+
+	if _, err := client.CreateJob(ctx, bellRequest(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	// retries go through once the fault is consumed
+	server.Inject(Fault{Path: "/jobs/*", Status: http.StatusTooManyRequests})
+	retrying := ionq.NewClient("", DefaultAPIKey,
+		ionq.WithBaseURL(ts.URL),
+		ionq.WithRetryPolicy(ionq.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+	)
+	if _, err := retrying.GetJob(ctx, &ionq.GetJobRequest{ID: server.Jobs()[0].ID}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInvalidAndFailedJobs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, New())
+
+	_, err := client.CreateJob(ctx, &ionq.CreateJobRequest{Target: "unknown", Input: bellRequest(t).Input})
+	if !errors.Is(err, ionq.ErrBadRequest) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the simulator target accepts more qubits than the local simulator runs
+	input, err := ionq.NewCircuit(21).H(20).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := client.CreateJob(ctx, &ionq.CreateJobRequest{Input: input})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.WaitForJob(ctx, created.Response.ID, nil); !errors.Is(err, ionq.ErrJobFailed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBatchJob(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := newTestClient(t, New())
+
+	var circuits []ionq.BatchCircuit
+	for name, theta := range map[string]float64{"zero": 0, "pi": math.Pi} {
+		input, err := ionq.NewCircuit(1).RX(0, theta).Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		circuits = append(circuits, ionq.BatchCircuit{Name: name, Input: input})
+	}
+
+	created, err := client.CreateBatchJob(ctx, &ionq.CreateJobRequest{Shots: 100}, circuits...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs, err := client.GetJobOutputs(ctx, created.Response.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]ionq.GetJobOutputResponse{"zero": {"0": 1}, "pi": {"1": 1}}
+	if diff := deep.Equal(expected, outputs); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
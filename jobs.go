@@ -5,8 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"slices"
 
 	"github.com/google/go-querystring/query"
 )
@@ -14,34 +14,29 @@ import (
 const jobsPath = "jobs"
 
 type Job struct {
-	ID     string `json:"id,omitempty"`
-	Name   string `json:"name,omitempty"`
-	Status string `json:"status,omitempty"`
-	Target string `json:"target,omitempty"`
+	ID     string    `json:"id,omitempty"`
+	Name   string    `json:"name,omitempty"`
+	Status JobStatus `json:"status,omitempty"`
+	Target Target    `json:"target,omitempty"`
 	Noise  struct {
-		Model string `json:"model,omitempty"`
-		Seed  int    `json:"seed,omitempty"`
+		Model NoiseModel `json:"model,omitempty"`
+		Seed  int        `json:"seed,omitempty"`
 	} `json:"noise,omitempty"`
-	Metadata struct {
-		CustomKey string `json:"custom_key,omitempty"`
-	} `json:"metadata,omitempty"`
-	Shots           int `json:"shots,omitempty"`
+	Metadata        Metadata `json:"metadata,omitempty"`
+	Shots           int      `json:"shots,omitempty"`
 	ErrorMitigation struct {
 		Debias bool `json:"debias,omitempty"`
 	} `json:"error_mitigation,omitempty"`
-	GateCounts struct {
-		OneQ int `json:"1q,omitempty"`
-		TwoQ int `json:"2q,omitempty"`
-	} `json:"gate_counts,omitempty"`
-	Qubits                 int      `json:"qubits,omitempty"`
-	CostUsd                float64  `json:"cost_usd,omitempty"`
-	Request                int      `json:"request,omitempty"`
-	Start                  int      `json:"start,omitempty"`
-	Response               int      `json:"response,omitempty"`
-	ExecutionTime          int      `json:"execution_time,omitempty"`
-	PredictedExecutionTime int      `json:"predicted_execution_time,omitempty"`
-	Children               []string `json:"children,omitempty"`
-	ResultsURL             string   `json:"results_url,omitempty"`
+	GateCounts             GateCounts `json:"gate_counts,omitempty"`
+	Qubits                 int        `json:"qubits,omitempty"`
+	CostUsd                float64    `json:"cost_usd,omitempty"`
+	Request                int        `json:"request,omitempty"`
+	Start                  int        `json:"start,omitempty"`
+	Response               int        `json:"response,omitempty"`
+	ExecutionTime          int        `json:"execution_time,omitempty"`
+	PredictedExecutionTime int        `json:"predicted_execution_time,omitempty"`
+	Children               []string   `json:"children,omitempty"`
+	ResultsURL             string     `json:"results_url,omitempty"`
 	Failure                struct {
 		Error string `json:"error,omitempty"`
 		Code  string `json:"code,omitempty"`
@@ -58,17 +53,30 @@ type GetJobsResponse struct {
 }
 
 type GetJobsRequest struct {
-	IDs    []string `url:"id"`
-	Status string   `url:"status"`
-	Limit  uint     `url:"limit"`
-	Next   string   `url:"next"`
+	IDs    []string  `url:"id"`
+	Status JobStatus `url:"status"`
+	Limit  uint      `url:"limit"`
+	Next   string    `url:"next"`
 }
 
 type GetJobRequest struct {
 	ID string `url:"id"`
 }
 
-type GetJobOutputRequest GetJobRequest
+// GetJobOutputRequest selects the job whose output is retrieved and how it is
+// aggregated.
+type GetJobOutputRequest struct {
+	ID string `url:"-"`
+
+	// Sharpen aggregates the output of a debiased job by plurality voting
+	// instead of averaging the symmetrized variants of the circuit. It has no
+	// effect on jobs created without ErrorMitigationInput.Debias.
+	Sharpen bool `url:"sharpen,omitempty"`
+
+	// Precision is the number of decimal places of the returned
+	// probabilities, the API default when zero.
+	Precision uint `url:"precision,omitempty"`
+}
 
 // these have the same structure
 type DeleteJobRequest GetJobRequest
@@ -92,24 +100,57 @@ type GetJobsResponseWithStatus struct {
 }
 
 type CircuitInput struct {
-	Gate     string `json:"gate,omitempty"`
-	Target   *uint  `json:"target,omitempty"`
-	Targets  []uint `json:"targets,omitempty`
-	Control  uint   `json:"control,omitempty"`
-	Controls []uint `json:"controls,omitempty"`
-	Rotation int    `json:"rotation,omitempty"`
+	Gate     string  `json:"gate,omitempty"`
+	Target   *uint   `json:"target,omitempty"`
+	Targets  []uint  `json:"targets,omitempty"`
+	Control  *uint   `json:"control,omitempty"`
+	Controls []uint  `json:"controls,omitempty"`
+	Rotation float64 `json:"rotation,omitempty"`
+
+	// native gate parameters, in turns
+	Phase  *float64  `json:"phase,omitempty"`
+	Phases []float64 `json:"phases,omitempty"`
+	Angle  *float64  `json:"angle,omitempty"`
+}
+
+// MarshalJSON encodes the gate, always including the rotation of rotation
+// gates, since a zero rotation would otherwise be dropped by omitempty.
+func (c CircuitInput) MarshalJSON() ([]byte, error) {
+	type circuitInput CircuitInput
+	gate := struct {
+		circuitInput
+		Rotation *float64 `json:"rotation,omitempty"`
+	}{circuitInput: circuitInput(c)}
+
+	if c.Rotation != 0 || slices.Contains(rotationGates, c.Gate) {
+		gate.Rotation = &c.Rotation
+	}
+	return json.Marshal(gate)
 }
 
 type JobInput struct {
 	Circuit []CircuitInput `json:"circuit,omitempty"`
-	Qubits  uint           `json:"qubits"`
+	Qubits  uint           `json:"qubits,omitempty"`
 	Format  string         `json:"format,omitempty"`
 	Gateset string         `json:"gateset,omitempty"`
+
+	// Circuits holds the circuits of a multicircuit job, in place of Circuit
+	Circuits []NamedCircuit `json:"circuits,omitempty"`
+
+	// Data holds the program when Format is FormatOpenQASM
+	Data string `json:"data,omitempty"`
+}
+
+// NamedCircuit is one of the circuits of a multicircuit job. Each circuit runs
+// as a child job named after it.
+type NamedCircuit struct {
+	Name    string         `json:"name,omitempty"`
+	Circuit []CircuitInput `json:"circuit"`
 }
 
 type NoiseInput struct {
-	Model string `json:"model,omitempty"`
-	Seed  int    `json:"seed,omitempty"`
+	Model NoiseModel `json:"model,omitempty"`
+	Seed  int        `json:"seed,omitempty"`
 }
 
 type ErrorMitigationInput struct {
@@ -118,17 +159,17 @@ type ErrorMitigationInput struct {
 
 type CreateJobRequest struct {
 	Name            string                `json:"name,omitempty"`
-	Metadata        map[string]string     `json:"metadata,omitempty"`
+	Metadata        Metadata              `json:"metadata,omitempty"`
 	Shots           uint                  `json:"shots,omitempty"`
-	Target          string                `json:"target,omitempty"`
+	Target          Target                `json:"target,omitempty"`
 	Noise           *NoiseInput           `json:"noise,omitempty"`
 	Input           JobInput              `json:"input,omitempty"`
 	ErrorMitigation *ErrorMitigationInput `json:"error_mitigation,omitempty"`
 }
 
 type CreateJobResponse struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
+	ID     string    `json:"id"`
+	Status JobStatus `json:"status"`
 }
 
 type CreateJobResponseWithStatus struct {
@@ -151,8 +192,8 @@ type DeleteJobResponse struct {
 }
 
 type CancelJobResponse struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
+	ID     string    `json:"id"`
+	Status JobStatus `json:"status"`
 }
 
 type CancelJobResponseWithStatus struct {
@@ -175,6 +216,7 @@ type GetJobOutputResponseWithStatus struct {
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("apiKey %s", c.apiKey))
+	req.Header.Set("User-Agent", c.userAgent)
 }
 
 // GetJobs retrieves a list of jobs from the IonQ API and returns the response
@@ -182,6 +224,12 @@ func (c *Client) setHeaders(req *http.Request) {
 func (c *Client) GetJobs(ctx context.Context, getJobsRequest *GetJobsRequest) (*GetJobsResponseWithStatus, error) {
 	url := c.makeURL(jobsPath)
 
+	if getJobsRequest.Status != "" {
+		if err := getJobsRequest.Status.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	v, err := query.Values(&getJobsRequest)
 	if err != nil {
 		return nil, err
@@ -196,13 +244,7 @@ func (c *Client) GetJobs(ctx context.Context, getJobsRequest *GetJobsRequest) (*
 
 	c.setHeaders(req)
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
+	body, status, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -214,35 +256,56 @@ func (c *Client) GetJobs(ctx context.Context, getJobsRequest *GetJobsRequest) (*
 
 	return &GetJobsResponseWithStatus{
 		Response: jobsResponse,
-		Status:   res.StatusCode,
+		Status:   status,
 	}, nil
 }
 
 // CreateJob creates a new job in the IonQ API and returns the response according
-// to the API documentation.
+// to the API documentation. Jobs above the budget set with WithBudget are
+// refused with a *BudgetError.
 func (c *Client) CreateJob(ctx context.Context, createJobRequest *CreateJobRequest) (*CreateJobResponseWithStatus, error) {
-	url := c.makeURL(jobsPath)
+	if c.budget == nil {
+		return c.createJob(ctx, createJobRequest)
+	}
 
-	reqBody, err := json.Marshal(createJobRequest)
+	if c.budget.PerDay > 0 {
+		c.budgetMu.Lock()
+		defer c.budgetMu.Unlock()
+	}
+
+	estimate, err := c.checkBudget(ctx, createJobRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
+	createJobResponseWithStatus, err := c.createJob(ctx, createJobRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	c.setHeaders(req)
+	if err := c.budget.Tracker.RecordSpend(ctx, createJobResponseWithStatus.Response.ID, estimate); err != nil {
+		return createJobResponseWithStatus, err
+	}
+
+	return createJobResponseWithStatus, nil
+}
+
+func (c *Client) createJob(ctx context.Context, createJobRequest *CreateJobRequest) (*CreateJobResponseWithStatus, error) {
+	url := c.makeURL(jobsPath)
+
+	reqBody, err := json.Marshal(createJobRequest)
+	if err != nil {
+		return nil, err
+	}
 
-	res, err := c.client.Do(req)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, err
 	}
 
-	defer res.Body.Close()
+	c.setHeaders(req)
 
-	body, err := io.ReadAll(res.Body)
+	body, status, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -253,7 +316,7 @@ func (c *Client) CreateJob(ctx context.Context, createJobRequest *CreateJobReque
 		return nil, err
 	}
 
-	createJobResponseWithStatus.Status = res.StatusCode
+	createJobResponseWithStatus.Status = status
 
 	return &createJobResponseWithStatus, nil
 }
@@ -275,14 +338,7 @@ func (c *Client) DeleteManyJobs(ctx context.Context, deleteManyJobsRequest *Dele
 
 	c.setHeaders(req)
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
+	body, status, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -293,7 +349,7 @@ func (c *Client) DeleteManyJobs(ctx context.Context, deleteManyJobsRequest *Dele
 		return nil, err
 	}
 
-	deleteManyJobsResponseWithStatus.Status = res.StatusCode
+	deleteManyJobsResponseWithStatus.Status = status
 
 	return &deleteManyJobsResponseWithStatus, nil
 }
@@ -312,13 +368,7 @@ func (c *Client) GetJob(ctx context.Context, getJobRequest *GetJobRequest) (*Get
 
 	c.setHeaders(req)
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
+	body, status, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -330,29 +380,41 @@ func (c *Client) GetJob(ctx context.Context, getJobRequest *GetJobRequest) (*Get
 
 	return &GetJobResponseWithStatus{
 		Response: getJobResponse,
-		Status:   res.StatusCode,
+		Status:   status,
 	}, nil
 }
 
+func (c *Client) jobOutputURL(getJobOutputRequest *GetJobOutputRequest) (string, error) {
+	url := c.makeURL(fmt.Sprintf("%s/%s/results", jobsPath, getJobOutputRequest.ID))
+
+	v, err := query.Values(getJobOutputRequest)
+	if err != nil {
+		return "", err
+	}
+
+	if len(v) > 0 {
+		url += fmt.Sprintf("?%s", v.Encode())
+	}
+
+	return url, nil
+}
+
 // GetJobOutput retrieves the output of a job from the IonQ API and returns the
 // response according to the API documentation.
 func (c *Client) GetJobOutput(ctx context.Context, getJobOutputRequest *GetJobOutputRequest) (*GetJobOutputResponseWithStatus, error) {
-	url := c.makeURL(fmt.Sprintf("%s/%s/results", jobsPath, getJobOutputRequest.ID))
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	url, err := c.jobOutputURL(getJobOutputRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	c.setHeaders(req)
-
-	res, err := c.client.Do(req)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
+	c.setHeaders(req)
+
+	body, status, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +426,7 @@ func (c *Client) GetJobOutput(ctx context.Context, getJobOutputRequest *GetJobOu
 
 	return &GetJobOutputResponseWithStatus{
 		Response: getJobOutputResponse,
-		Status:   res.StatusCode,
+		Status:   status,
 	}, nil
 }
 
@@ -380,13 +442,7 @@ func (c *Client) DeleteJob(ctx context.Context, deleteJobRequest *DeleteJobReque
 
 	c.setHeaders(req)
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
+	body, status, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -398,7 +454,7 @@ func (c *Client) DeleteJob(ctx context.Context, deleteJobRequest *DeleteJobReque
 
 	return &DeleteJobResponseWithStatus{
 		Response: deleteJobResponse,
-		Status:   res.StatusCode,
+		Status:   status,
 	}, nil
 }
 
@@ -414,13 +470,7 @@ func (c *Client) CancelJob(ctx context.Context, cancelJobRequest *CancelJobReque
 
 	c.setHeaders(req)
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
+	body, status, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -432,6 +482,6 @@ func (c *Client) CancelJob(ctx context.Context, cancelJobRequest *CancelJobReque
 
 	return &CancelJobResponseWithStatus{
 		Response: cancelJobResponse,
-		Status:   res.StatusCode,
+		Status:   status,
 	}, nil
 }
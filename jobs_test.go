@@ -176,18 +176,8 @@ func TestGetJobsErrorStatusCode(t *testing.T) {
 		JSON(&jobsResponseMock)
 
 	client := NewClient(myFakeEndpoint, myFakeAPIKey)
-	jobsResponseWithStatus, err := client.GetJobs(ctx, &GetJobsRequest{})
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	if jobsResponseWithStatus.Status != http.StatusBadRequest {
-		t.Fatalf("unexpected status: %d", jobsResponseWithStatus.Status)
-	}
-
-	if diff := deep.Equal(jobsResponseMock, jobsResponseWithStatus.Response); len(diff) > 0 {
-		t.Fatalf("unexpected diff: %s", diff)
-	}
+	_, err = client.GetJobs(ctx, &GetJobsRequest{})
+	assertAPIError(t, err, http.StatusBadRequest, ErrBadRequest)
 }
 
 func TestCreateJobsSuccess(t *testing.T) {
@@ -250,14 +240,8 @@ func TestCreateJobsFailure(t *testing.T) {
 		JSON(&createJobResponse)
 
 	client := NewClient(myFakeEndpoint, myFakeAPIKey)
-	createJobResponseWithStatus, err := client.CreateJob(ctx, &CreateJobRequest{})
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	if createJobResponseWithStatus.Status != 400 {
-		t.Fatalf("unexpected status code %d", createJobResponseWithStatus.Status)
-	}
+	_, err = client.CreateJob(ctx, &CreateJobRequest{})
+	assertAPIError(t, err, http.StatusBadRequest, ErrBadRequest)
 }
 
 func TestDeleteManyJobsSuccess(t *testing.T) {
@@ -320,14 +304,8 @@ func TestDeleteManyJobsFailure(t *testing.T) {
 		JSON(&deleteManyJobsResponse)
 
 	client := NewClient(myFakeEndpoint, myFakeAPIKey)
-	deleteManyJobsResponseWithStatus, err := client.DeleteManyJobs(ctx, &DeleteManyJobsRequest{})
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	if deleteManyJobsResponseWithStatus.Status != 400 {
-		t.Fatalf("unexpected response code: %d", deleteManyJobsResponseWithStatus.Status)
-	}
+	_, err = client.DeleteManyJobs(ctx, &DeleteManyJobsRequest{})
+	assertAPIError(t, err, http.StatusBadRequest, ErrBadRequest)
 }
 
 func newGock() *gock.Request {
@@ -398,16 +376,10 @@ func TestGetJobFailure(t *testing.T) {
 		JSON(&jobResponseMock)
 
 	client := NewClient(myFakeEndpoint, myFakeAPIKey)
-	jobResponseWithStatus, err := client.GetJob(ctx, &GetJobRequest{
+	_, err = client.GetJob(ctx, &GetJobRequest{
 		ID: "some-id",
 	})
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	if jobResponseWithStatus.Status != 400 {
-		t.Fatalf("unexpected status %d", jobResponseWithStatus.Status)
-	}
+	assertAPIError(t, err, http.StatusBadRequest, ErrBadRequest)
 }
 
 func TestDeleteJobSuccess(t *testing.T) {
@@ -472,16 +444,10 @@ func TestDeleteJobFailure(t *testing.T) {
 		JSON(&jobResponseMock)
 
 	client := NewClient(myFakeEndpoint, myFakeAPIKey)
-	jobResponseWithStatus, err := client.DeleteJob(ctx, &DeleteJobRequest{
+	_, err = client.DeleteJob(ctx, &DeleteJobRequest{
 		ID: "some-id",
 	})
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	if jobResponseWithStatus.Status != 400 {
-		t.Fatalf("unexpected status %d", jobResponseWithStatus.Status)
-	}
+	assertAPIError(t, err, http.StatusBadRequest, ErrBadRequest)
 }
 
 func TestGetJobOutputSuccess(t *testing.T) {
@@ -524,6 +490,40 @@ func TestGetJobOutputSuccess(t *testing.T) {
 	}
 }
 
+func TestGetJobOutputQueryOptions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	jobResponseMock := GetJobOutputResponse{"0": 0.125, "3": 0.875}
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id/results", jobsPath)).
+		MatchParam("sharpen", "true").
+		MatchParam("precision", "3").
+		Reply(200).
+		JSON(&jobResponseMock)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	jobResponseWithStatus, err := client.GetJobOutput(ctx, &GetJobOutputRequest{
+		ID:        "some-id",
+		Sharpen:   true,
+		Precision: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(jobResponseMock, jobResponseWithStatus.Response); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if !gock.IsDone() {
+		t.Fatal("expected the query options to be sent")
+	}
+}
+
 func TestCancelJobSuccess(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
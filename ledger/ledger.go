@@ -0,0 +1,286 @@
+// Package ledger records the spend of IonQ jobs and enforces monthly budgets
+// before they are submitted.
+//
+// A Ledger wraps an ionq.Client: jobs created through it are recorded with
+// their estimated cost, Sync replaces estimates with the cost reported by the
+// API once jobs complete, and Import records jobs created elsewhere. Entries
+// are persisted to a Store, a JSON file or a SQL table.
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"ionq"
+)
+
+// DefaultProjectKey is the metadata key holding the project of a job.
+const DefaultProjectKey = "project"
+
+// Budget limits the spend of a calendar month, in UTC. Project and Target
+// restrict the budget to the jobs of a project or target; empty values match
+// every job.
+type Budget struct {
+	Name    string
+	Project string
+	Target  ionq.Target
+	Monthly float64
+}
+
+func (b Budget) matches(project string, target ionq.Target) bool {
+	return (b.Project == "" || b.Project == project) && (b.Target == "" || b.Target == target)
+}
+
+// BudgetError is returned by Ledger.CreateJob when a job would bring the
+// month's spend above a budget. The job is not submitted.
+type BudgetError struct {
+	Budget   Budget
+	Estimate *ionq.Estimate
+	Spent    float64
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("ledger: job estimated at $%.2f exceeds budget %q of $%.2f a month, $%.2f already spent", e.Estimate.CostUSD, e.Budget.Name, e.Budget.Monthly, e.Spent)
+}
+
+// Is reports whether target is ionq.ErrBudgetExceeded.
+func (e *BudgetError) Is(target error) bool {
+	return target == ionq.ErrBudgetExceeded
+}
+
+// Option configures a Ledger created with New.
+type Option func(*Ledger)
+
+// WithBudgets sets the budgets enforced by Ledger.CreateJob.
+func WithBudgets(budgets ...Budget) Option {
+	return func(l *Ledger) {
+		l.budgets = append(l.budgets, budgets...)
+	}
+}
+
+// WithEstimator sets the Estimator used to estimate jobs before submission,
+// the zero ionq.Estimator by default.
+func WithEstimator(estimator *ionq.Estimator) Option {
+	return func(l *Ledger) {
+		l.estimator = estimator
+	}
+}
+
+// WithProjectKey sets the metadata key holding the project of a job,
+// DefaultProjectKey by default.
+func WithProjectKey(key string) Option {
+	return func(l *Ledger) {
+		l.projectKey = key
+	}
+}
+
+// Ledger records the spend of the jobs of a Client.
+type Ledger struct {
+	client     *ionq.Client
+	store      Store
+	budgets    []Budget
+	estimator  *ionq.Estimator
+	projectKey string
+	now        func() time.Time
+
+	// mu serializes submissions so concurrent jobs cannot exceed a budget
+	// together
+	mu sync.Mutex
+}
+
+// New returns a Ledger recording the jobs of client in store.
+func New(client *ionq.Client, store Store, opts ...Option) *Ledger {
+	l := &Ledger{
+		client:     client,
+		store:      store,
+		estimator:  &ionq.Estimator{},
+		projectKey: DefaultProjectKey,
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// CreateJob estimates the job and checks it against every matching budget
+// before creating it with the Client, then records it with its estimated
+// cost. Jobs that cannot be estimated are refused.
+func (l *Ledger) CreateJob(ctx context.Context, createJobRequest *ionq.CreateJobRequest) (*ionq.CreateJobResponseWithStatus, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	estimate, err := l.estimator.Estimate(createJobRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	project := createJobRequest.Metadata.Get(l.projectKey)
+	now := l.now()
+
+	if err := l.checkBudgets(ctx, now, project, estimate); err != nil {
+		return nil, err
+	}
+
+	createJobResponseWithStatus, err := l.client.CreateJob(ctx, createJobRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	status := createJobResponseWithStatus.Response.Status
+	if status == "" {
+		status = ionq.JobStatusSubmitted
+	}
+
+	entry := Entry{
+		JobID:       createJobResponseWithStatus.Response.ID,
+		Name:        createJobRequest.Name,
+		Target:      estimate.Target,
+		Project:     project,
+		Metadata:    maps.Clone(createJobRequest.Metadata),
+		Status:      status,
+		CostUSD:     estimate.CostUSD,
+		Estimated:   true,
+		SubmittedAt: now.UTC(),
+	}
+
+	if err := l.store.Put(ctx, entry); err != nil {
+		return createJobResponseWithStatus, fmt.Errorf("ledger: job %s created but not recorded: %w", entry.JobID, err)
+	}
+
+	return createJobResponseWithStatus, nil
+}
+
+func (l *Ledger) checkBudgets(ctx context.Context, now time.Time, project string, estimate *ionq.Estimate) error {
+	if len(l.budgets) == 0 {
+		return nil
+	}
+
+	entries, err := l.store.Entries(ctx, monthStart(now), time.Time{})
+	if err != nil {
+		return err
+	}
+
+	for _, budget := range l.budgets {
+		if !budget.matches(project, estimate.Target) {
+			continue
+		}
+
+		var spent float64
+		for _, entry := range entries {
+			if budget.matches(entry.Project, entry.Target) {
+				spent += entry.CostUSD
+			}
+		}
+
+		if spent+estimate.CostUSD > budget.Monthly {
+			return &BudgetError{Budget: budget, Estimate: estimate, Spent: spent}
+		}
+	}
+
+	return nil
+}
+
+// Sync fetches the jobs whose cost is still estimated and records the cost
+// reported by the API for those that reached a terminal state. It returns
+// the number of entries updated.
+func (l *Ledger) Sync(ctx context.Context) (int, error) {
+	entries, err := l.store.Entries(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, entry := range entries {
+		if !entry.Estimated {
+			continue
+		}
+
+		jobResponseWithStatus, err := l.client.GetJob(ctx, &ionq.GetJobRequest{ID: entry.JobID})
+		if err != nil {
+			return updated, fmt.Errorf("ledger: job %s: %w", entry.JobID, err)
+		}
+
+		job := ionq.Job(jobResponseWithStatus.Response)
+		if job.Status == entry.Status && !job.Status.IsTerminal() {
+			continue
+		}
+
+		entry.Status = job.Status
+		if job.Status.IsTerminal() {
+			entry.CostUSD = job.CostUsd
+			entry.Estimated = false
+		}
+
+		if err := l.store.Put(ctx, entry); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// Import records the terminal jobs matching filter with the cost reported by
+// the API, including jobs created without the Ledger. The project and
+// metadata of jobs already recorded are kept. It returns the number of jobs
+// recorded.
+func (l *Ledger) Import(ctx context.Context, filter *ionq.GetJobsRequest) (int, error) {
+	entries, err := l.store.Entries(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := make(map[string]Entry, len(entries))
+	for _, entry := range entries {
+		recorded[entry.JobID] = entry
+	}
+
+	imported := 0
+	for job, err := range l.client.ListJobs(ctx, filter) {
+		if err != nil {
+			return imported, err
+		}
+
+		if !job.Status.IsTerminal() {
+			continue
+		}
+
+		entry, ok := recorded[job.ID]
+		if !ok {
+			entry = Entry{
+				JobID:       job.ID,
+				Metadata:    maps.Clone(job.Metadata),
+				SubmittedAt: time.Unix(int64(job.Request), 0).UTC(),
+			}
+			entry.Project = entry.Metadata.Get(l.projectKey)
+		}
+
+		if ok && !entry.Estimated && entry.Status == job.Status {
+			continue
+		}
+
+		entry.Name = job.Name
+		entry.Target = job.Target
+		entry.Status = job.Status
+		entry.CostUSD = job.CostUsd
+		entry.Estimated = false
+
+		if err := l.store.Put(ctx, entry); err != nil {
+			return imported, err
+		}
+		imported++
+	}
+
+	return imported, nil
+}
@@ -0,0 +1,232 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+
+	"ionq"
+)
+
+const (
+	myFakeEndpoint = "https://myfakeionq.test/v0.3"
+	myFakeAPIKey   = "my-fake-api-key"
+)
+
+func newGock() *gock.Request {
+	return gock.New(myFakeEndpoint).
+		MatchHeader("Authorization", fmt.Sprintf("apiKey %s", myFakeAPIKey))
+}
+
+func newTestLedger(t *testing.T, opts ...Option) (*Ledger, Store) {
+	t.Helper()
+
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "ledger.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts = append([]Option{WithEstimator(&ionq.Estimator{
+		Pricing: ionq.PricingTable{
+			ionq.TargetAria1:     {MinimumPerCircuit: 40},
+			ionq.TargetSimulator: {},
+		},
+	})}, opts...)
+
+	l := New(ionq.NewClient(myFakeEndpoint, myFakeAPIKey), store, opts...)
+	l.now = func() time.Time { return time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC) }
+
+	return l, store
+}
+
+func testRequest(t *testing.T, project string) *ionq.CreateJobRequest {
+	t.Helper()
+
+	input, err := ionq.NewCircuit(1).H(0).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &ionq.CreateJobRequest{
+		Name:     "job",
+		Target:   ionq.TargetAria1,
+		Metadata: map[string]string{"project": project},
+		Input:    input,
+	}
+}
+
+func TestCreateJobBudgets(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	l, store := newTestLedger(t, WithBudgets(
+		Budget{Name: "chem", Project: "chem", Monthly: 100},
+		Budget{Name: "aria", Target: ionq.TargetAria1, Monthly: 150},
+	))
+
+	// an entry of the previous month does not count
+	previous := Entry{JobID: "old", Target: ionq.TargetAria1, Project: "chem", CostUSD: 1000, SubmittedAt: time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)}
+	if err := store.Put(ctx, previous); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, project := range []string{"chem", "chem", "physics"} {
+		newGock().
+			Post("jobs").
+			Reply(200).
+			JSON(&ionq.CreateJobResponse{ID: fmt.Sprintf("job-%d", i), Status: ionq.JobStatusReady})
+
+		if _, err := l.CreateJob(ctx, testRequest(t, project)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// chem spent 80 of 100
+	_, err := l.CreateJob(ctx, testRequest(t, "chem"))
+
+	var budgetErr *BudgetError
+	if !errors.As(err, &budgetErr) || budgetErr.Budget.Name != "chem" || budgetErr.Spent != 80 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// aria spent 120 of 150
+	_, err = l.CreateJob(ctx, testRequest(t, "physics"))
+	if !errors.As(err, &budgetErr) || budgetErr.Budget.Name != "aria" || budgetErr.Spent != 120 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !errors.Is(err, ionq.ErrBudgetExceeded) {
+		t.Fatalf("expected ionq.ErrBudgetExceeded, received %v", err)
+	}
+
+	entries, err := store.Entries(ctx, monthStart(l.now()), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 3 || !entries[0].Estimated || entries[0].CostUSD != 40 || entries[0].Project != "chem" || entries[0].Status != ionq.JobStatusReady {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	if !gock.IsDone() {
+		t.Fatal("expected every job within budget to be created")
+	}
+}
+
+func TestSync(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	l, store := newTestLedger(t)
+
+	submitted := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, entry := range []Entry{
+		{JobID: "done", Target: ionq.TargetAria1, Status: ionq.JobStatusReady, CostUSD: 40, Estimated: true, SubmittedAt: submitted},
+		{JobID: "running", Target: ionq.TargetAria1, Status: ionq.JobStatusReady, CostUSD: 40, Estimated: true, SubmittedAt: submitted},
+		{JobID: "synced", Target: ionq.TargetAria1, Status: ionq.JobStatusCompleted, CostUSD: 12, SubmittedAt: submitted},
+	} {
+		if err := store.Put(ctx, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newGock().
+		Get("jobs/done").
+		Reply(200).
+		JSON(&ionq.GetJobResponse{ID: "done", Status: ionq.JobStatusCompleted, CostUsd: 31.25})
+
+	newGock().
+		Get("jobs/running").
+		Reply(200).
+		JSON(&ionq.GetJobResponse{ID: "running", Status: ionq.JobStatusRunning})
+
+	updated, err := l.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated != 2 {
+		t.Fatalf("unexpected number of updated entries: %d", updated)
+	}
+
+	entries, err := store.Entries(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byID := make(map[string]Entry)
+	for _, entry := range entries {
+		byID[entry.JobID] = entry
+	}
+
+	if done := byID["done"]; done.Estimated || done.CostUSD != 31.25 || done.Status != ionq.JobStatusCompleted {
+		t.Fatalf("unexpected entry: %+v", done)
+	}
+
+	if running := byID["running"]; !running.Estimated || running.CostUSD != 40 || running.Status != ionq.JobStatusRunning {
+		t.Fatalf("unexpected entry: %+v", running)
+	}
+
+	if !gock.IsDone() {
+		t.Fatal("expected the estimated jobs to be fetched")
+	}
+}
+
+func TestImport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	l, store := newTestLedger(t)
+
+	// jobs recorded by the ledger keep their project
+	recorded := Entry{JobID: "recorded", Project: "chem", Status: ionq.JobStatusReady, CostUSD: 40, Estimated: true, SubmittedAt: time.Unix(1709251200, 0).UTC()}
+	if err := store.Put(ctx, recorded); err != nil {
+		t.Fatal(err)
+	}
+
+	newGock().
+		Get("jobs").
+		Reply(200).
+		JSON(&ionq.GetJobsResponse{Jobs: []ionq.Job{
+			{ID: "recorded", Status: ionq.JobStatusCompleted, Target: ionq.TargetAria1, CostUsd: 35, Request: 1709251200},
+			{ID: "external", Name: "external", Status: ionq.JobStatusCompleted, Target: ionq.TargetHarmony, CostUsd: 3, Request: 1709337600, Metadata: ionq.Metadata{"project": "physics"}},
+			{ID: "pending", Status: ionq.JobStatusRunning, Target: ionq.TargetHarmony},
+		}})
+
+	imported, err := l.Import(ctx, &ionq.GetJobsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imported != 2 {
+		t.Fatalf("unexpected number of imported jobs: %d", imported)
+	}
+
+	entries, err := store.Entries(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	if entries[0].JobID != "recorded" || entries[0].Project != "chem" || entries[0].CostUSD != 35 || entries[0].Estimated {
+		t.Fatalf("unexpected entry: %+v", entries[0])
+	}
+
+	if entries[1].JobID != "external" || entries[1].Target != ionq.TargetHarmony || entries[1].Project != "physics" || !entries[1].SubmittedAt.Equal(time.Unix(1709337600, 0)) {
+		t.Fatalf("unexpected entry: %+v", entries[1])
+	}
+}
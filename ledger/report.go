@@ -0,0 +1,73 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"ionq"
+)
+
+// Report is the spend of the jobs submitted in a period, in USD.
+type Report struct {
+	Since time.Time
+	Until time.Time
+
+	Jobs  int
+	Total float64
+
+	// Estimated is the part of Total still estimated, for jobs that did not
+	// complete or were not synced yet
+	Estimated float64
+
+	ByTarget  map[ionq.Target]float64
+	ByProject map[string]float64
+
+	// ByMetadata breaks the spend down by the value of each requested
+	// metadata key. Jobs without the key are counted under "".
+	ByMetadata map[string]map[string]float64
+}
+
+// Report returns the spend of the jobs submitted in [since, until), broken
+// down by target, project and the value of each of metadataKeys. A zero time
+// leaves that side unbounded.
+func (l *Ledger) Report(ctx context.Context, since, until time.Time, metadataKeys ...string) (*Report, error) {
+	entries, err := l.store.Entries(ctx, since, until)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Since:      since,
+		Until:      until,
+		ByTarget:   make(map[ionq.Target]float64),
+		ByProject:  make(map[string]float64),
+		ByMetadata: make(map[string]map[string]float64, len(metadataKeys)),
+	}
+
+	for _, key := range metadataKeys {
+		report.ByMetadata[key] = make(map[string]float64)
+	}
+
+	for _, entry := range entries {
+		report.Jobs++
+		report.Total += entry.CostUSD
+		if entry.Estimated {
+			report.Estimated += entry.CostUSD
+		}
+
+		report.ByTarget[entry.Target] += entry.CostUSD
+		report.ByProject[entry.Project] += entry.CostUSD
+
+		for _, key := range metadataKeys {
+			report.ByMetadata[key][entry.Metadata.Get(key)] += entry.CostUSD
+		}
+	}
+
+	return report, nil
+}
+
+// MonthReport returns the Report of the calendar month, in UTC, holding t.
+func (l *Ledger) MonthReport(ctx context.Context, t time.Time, metadataKeys ...string) (*Report, error) {
+	since := monthStart(t)
+	return l.Report(ctx, since, since.AddDate(0, 1, 0), metadataKeys...)
+}
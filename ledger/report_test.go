@@ -0,0 +1,55 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+func TestReport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l, store := newTestLedger(t)
+
+	for _, entry := range testEntries() {
+		if err := store.Put(ctx, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := l.MonthReport(ctx, time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), "project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Report{
+		Since:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		Until:     time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		Jobs:      2,
+		Total:     130,
+		Estimated: 10,
+		ByTarget:  map[ionq.Target]float64{ionq.TargetAria1: 120, ionq.TargetHarmony: 10},
+		ByProject: map[string]float64{"chem": 120, "": 10},
+		ByMetadata: map[string]map[string]float64{
+			"project": {"chem": 120, "": 10},
+		},
+	}
+
+	if diff := deep.Equal(expected, report); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	all, err := l.Report(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if all.Jobs != 3 || all.ByTarget[ionq.TargetSimulator] != 0 || len(all.ByMetadata) != 0 {
+		t.Fatalf("unexpected report: %+v", all)
+	}
+}
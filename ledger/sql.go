@@ -0,0 +1,136 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"ionq"
+)
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLStore is a Store keeping the entries in a database/sql table. The
+// statements use ? placeholders and INSERT ... ON CONFLICT, as supported by
+// SQLite; metadata is stored as JSON text and times as unix seconds.
+type SQLStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLStore returns a SQLStore using the given table, created if it does
+// not exist.
+func NewSQLStore(ctx context.Context, db *sql.DB, table string) (*SQLStore, error) {
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("ledger: invalid table name %q", table)
+	}
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	job_id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	target TEXT NOT NULL,
+	project TEXT NOT NULL,
+	metadata TEXT NOT NULL,
+	status TEXT NOT NULL,
+	cost_usd REAL NOT NULL,
+	estimated BOOLEAN NOT NULL,
+	submitted_at INTEGER NOT NULL
+)`, table))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLStore{db: db, table: table}, nil
+}
+
+func (s *SQLStore) Put(ctx context.Context, entry Entry) error {
+	metadata, err := json.Marshal(entry.Metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s
+	(job_id, name, target, project, metadata, status, cost_usd, estimated, submitted_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (job_id) DO UPDATE SET
+	name = excluded.name,
+	target = excluded.target,
+	project = excluded.project,
+	metadata = excluded.metadata,
+	status = excluded.status,
+	cost_usd = excluded.cost_usd,
+	estimated = excluded.estimated,
+	submitted_at = excluded.submitted_at`, s.table),
+		entry.JobID,
+		entry.Name,
+		string(entry.Target),
+		entry.Project,
+		string(metadata),
+		string(entry.Status),
+		entry.CostUSD,
+		entry.Estimated,
+		entry.SubmittedAt.Unix(),
+	)
+	return err
+}
+
+func (s *SQLStore) Entries(ctx context.Context, since, until time.Time) ([]Entry, error) {
+	// unbounded sides use the widest unix range instead of a second query
+	start, end := int64(-1<<63), int64(1<<63-1)
+	if !since.IsZero() {
+		start = since.Unix()
+	}
+	if !until.IsZero() {
+		end = until.Unix()
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT
+	job_id, name, target, project, metadata, status, cost_usd, estimated, submitted_at
+FROM %s
+WHERE submitted_at >= ? AND submitted_at < ?
+ORDER BY submitted_at, job_id`, s.table), start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var (
+			entry       Entry
+			target      string
+			status      string
+			metadata    string
+			submittedAt int64
+		)
+
+		err := rows.Scan(
+			&entry.JobID,
+			&entry.Name,
+			&target,
+			&entry.Project,
+			&metadata,
+			&status,
+			&entry.CostUSD,
+			&entry.Estimated,
+			&submittedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(metadata), &entry.Metadata); err != nil {
+			return nil, fmt.Errorf("ledger: metadata of job %s: %w", entry.JobID, err)
+		}
+
+		entry.Target = ionq.Target(target)
+		entry.Status = ionq.JobStatus(status)
+		entry.SubmittedAt = time.Unix(submittedAt, 0).UTC()
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
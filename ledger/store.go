@@ -0,0 +1,157 @@
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"ionq"
+)
+
+// Entry is the spend recorded for a job.
+type Entry struct {
+	JobID    string         `json:"job_id"`
+	Name     string         `json:"name,omitempty"`
+	Target   ionq.Target    `json:"target,omitempty"`
+	Project  string         `json:"project,omitempty"`
+	Metadata ionq.Metadata  `json:"metadata,omitempty"`
+	Status   ionq.JobStatus `json:"status,omitempty"`
+
+	// CostUSD is the estimate made before submission until the job
+	// completes, and the cost reported by the API afterwards.
+	CostUSD   float64 `json:"cost_usd"`
+	Estimated bool    `json:"estimated,omitempty"`
+
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// Store persists the entries of a Ledger. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Put inserts the entry, or replaces the entry with the same JobID.
+	Put(ctx context.Context, entry Entry) error
+
+	// Entries returns the entries submitted in [since, until), ordered by
+	// submission time. A zero time leaves that side unbounded.
+	Entries(ctx context.Context, since, until time.Time) ([]Entry, error)
+}
+
+func inRange(t, since, until time.Time) bool {
+	return (since.IsZero() || !t.Before(since)) && (until.IsZero() || t.Before(until))
+}
+
+func sortEntries(entries []Entry) {
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if c := a.SubmittedAt.Compare(b.SubmittedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.JobID, b.JobID)
+	})
+}
+
+// FileStore is a Store keeping the entries in a JSON file, rewritten on
+// every Put. It suits a single process; use SQLStore to share a ledger.
+type FileStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// NewFileStore returns a FileStore backed by the file at path, loading its
+// entries if it exists.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		s.entries[entry.JobID] = entry
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) Put(_ context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.entries[entry.JobID]
+	s.entries[entry.JobID] = entry
+
+	if err := s.save(); err != nil {
+		if existed {
+			s.entries[entry.JobID] = previous
+		} else {
+			delete(s.entries, entry.JobID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *FileStore) Entries(_ context.Context, since, until time.Time) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []Entry
+	for _, entry := range s.entries {
+		if inRange(entry.SubmittedAt, since, until) {
+			entries = append(entries, entry)
+		}
+	}
+	sortEntries(entries)
+
+	return entries, nil
+}
+
+// save writes the entries to a temporary file renamed over the ledger, so
+// the file is never left half written.
+func (s *FileStore) save() error {
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	sortEntries(entries)
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,227 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+// fakeDriver is a database/sql driver understanding the statements of
+// SQLStore, keeping rows in memory per data source name.
+type fakeDriver struct {
+	mu     sync.Mutex
+	tables map[string]map[string][]driver.Value
+}
+
+var testDriver = &fakeDriver{tables: make(map[string]map[string][]driver.Value)}
+
+func init() {
+	sql.Register("ledgerfake", testDriver)
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.tables[name] == nil {
+		d.tables[name] = make(map[string][]driver.Value)
+	}
+	return &fakeConn{driver: d, rows: d.tables[name]}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+	rows   map[string][]driver.Value
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: strings.TrimSpace(query)}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.driver.mu.Lock()
+	defer s.conn.driver.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE"):
+	case strings.HasPrefix(s.query, "INSERT INTO"):
+		s.conn.rows[args[0].(string)] = slices.Clone(args)
+	default:
+		return nil, errors.New("unexpected statement: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.driver.mu.Lock()
+	defer s.conn.driver.mu.Unlock()
+
+	if !strings.HasPrefix(s.query, "SELECT") {
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+
+	start, end := args[0].(int64), args[1].(int64)
+
+	var rows [][]driver.Value
+	for _, row := range s.conn.rows {
+		if submitted := row[8].(int64); submitted >= start && submitted < end {
+			rows = append(rows, row)
+		}
+	}
+
+	slices.SortFunc(rows, func(a, b []driver.Value) int {
+		if c := a[8].(int64) - b[8].(int64); c != 0 {
+			return int(c)
+		}
+		return strings.Compare(a[0].(string), b[0].(string))
+	})
+
+	return &fakeRows{rows: rows}, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"job_id", "name", "target", "project", "metadata", "status", "cost_usd", "estimated", "submitted_at"}
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func testEntries() []Entry {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []Entry{
+		{JobID: "job-2", Target: ionq.TargetAria1, Project: "chem", Metadata: map[string]string{"project": "chem"}, Status: ionq.JobStatusCompleted, CostUSD: 120, SubmittedAt: day.AddDate(0, 0, 3)},
+		{JobID: "job-1", Target: ionq.TargetHarmony, Status: ionq.JobStatusReady, CostUSD: 10, Estimated: true, SubmittedAt: day},
+		{JobID: "job-3", Target: ionq.TargetSimulator, Status: ionq.JobStatusCompleted, SubmittedAt: day.AddDate(0, 1, 0)},
+	}
+}
+
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entries := testEntries()
+	for _, entry := range entries {
+		if err := store.Put(ctx, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// replacing an entry keeps a single entry per job
+	entries[1].Status = ionq.JobStatusCompleted
+	entries[1].CostUSD = 8.5
+	entries[1].Estimated = false
+	if err := store.Put(ctx, entries[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := store.Entries(ctx, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal([]Entry{entries[1], entries[0], entries[2]}, all); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	march, err := store.Entries(ctx, entries[1].SubmittedAt, entries[2].SubmittedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal([]Entry{entries[1], entries[0]}, march); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.json")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testStore(t, store)
+
+	// a new store loads the saved entries
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := reloaded.Entries(context.Background(), time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 3 || entries[0].CostUSD != 8.5 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestSQLStore(t *testing.T) {
+	db, err := sql.Open("ledgerfake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := NewSQLStore(context.Background(), db, "ionq_ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testStore(t, store)
+}
+
+func TestSQLStoreInvalidTable(t *testing.T) {
+	db, err := sql.Open("ledgerfake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := NewSQLStore(context.Background(), db, "ledger; DROP TABLE jobs"); err == nil {
+		t.Fatal("expected invalid table name error")
+	}
+}
@@ -0,0 +1,161 @@
+package ionq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+)
+
+// Metadata keys of the tagging conventions with typed accessors.
+const (
+	MetadataExperiment = "experiment"
+	MetadataOwner      = "owner"
+	MetadataCommit     = "commit"
+)
+
+// Limits of the metadata accepted by the API.
+const (
+	MaxMetadataKeys        = 10
+	MaxMetadataKeyLength   = 63
+	MaxMetadataValueLength = 400
+)
+
+// ErrInvalidMetadata is wrapped by the errors returned by Metadata.Validate.
+var ErrInvalidMetadata = errors.New("ionq: invalid metadata")
+
+// Metadata holds the string key-value pairs attached to a job.
+//
+// Decoding is lenient: values that are not strings are kept as their JSON
+// text and null values are dropped, so jobs created by other clients can
+// always be read.
+type Metadata map[string]string
+
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw == nil {
+		*m = nil
+		return nil
+	}
+
+	metadata := make(Metadata, len(raw))
+	for key, value := range raw {
+		switch {
+		case bytes.Equal(value, []byte("null")):
+		case len(value) > 0 && value[0] == '"':
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				return err
+			}
+			metadata[key] = s
+		default:
+			metadata[key] = string(value)
+		}
+	}
+
+	*m = metadata
+	return nil
+}
+
+// Get returns the value of key, "" when it is not set.
+func (m Metadata) Get(key string) string {
+	return m[key]
+}
+
+// Experiment returns the value of the MetadataExperiment key.
+func (m Metadata) Experiment() string { return m.Get(MetadataExperiment) }
+
+// Owner returns the value of the MetadataOwner key.
+func (m Metadata) Owner() string { return m.Get(MetadataOwner) }
+
+// Commit returns the value of the MetadataCommit key.
+func (m Metadata) Commit() string { return m.Get(MetadataCommit) }
+
+// With returns a copy of m with key set to value, so tags can be chained
+// from a nil Metadata.
+func (m Metadata) With(key, value string) Metadata {
+	metadata := maps.Clone(m)
+	if metadata == nil {
+		metadata = make(Metadata, 1)
+	}
+	metadata[key] = value
+	return metadata
+}
+
+// WithExperiment returns a copy of m with the MetadataExperiment key set.
+func (m Metadata) WithExperiment(experiment string) Metadata {
+	return m.With(MetadataExperiment, experiment)
+}
+
+// WithOwner returns a copy of m with the MetadataOwner key set.
+func (m Metadata) WithOwner(owner string) Metadata { return m.With(MetadataOwner, owner) }
+
+// WithCommit returns a copy of m with the MetadataCommit key set.
+func (m Metadata) WithCommit(commit string) Metadata { return m.With(MetadataCommit, commit) }
+
+// Matches reports whether m holds every key of selector with the same value.
+// An empty selector matches any metadata.
+func (m Metadata) Matches(selector Metadata) bool {
+	for key, value := range selector {
+		if actual, ok := m[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks m against the limits of the API on the number of keys and
+// the length of keys and values.
+func (m Metadata) Validate() error {
+	if len(m) > MaxMetadataKeys {
+		return fmt.Errorf("%w: %d keys, at most %d are allowed", ErrInvalidMetadata, len(m), MaxMetadataKeys)
+	}
+
+	for key, value := range m {
+		if key == "" || len(key) > MaxMetadataKeyLength {
+			return fmt.Errorf("%w: key %q must have 1 to %d characters", ErrInvalidMetadata, key, MaxMetadataKeyLength)
+		}
+		if len(value) > MaxMetadataValueLength {
+			return fmt.Errorf("%w: value of %q is longer than %d characters", ErrInvalidMetadata, key, MaxMetadataValueLength)
+		}
+	}
+
+	return nil
+}
+
+// FilterJobsByMetadata returns the jobs whose metadata matches selector, for
+// example to filter the Jobs of a GetJobs response.
+func FilterJobsByMetadata(jobs []Job, selector Metadata) []Job {
+	var filtered []Job
+	for _, job := range jobs {
+		if job.Metadata.Matches(selector) {
+			filtered = append(filtered, job)
+		}
+	}
+	return filtered
+}
+
+// ListJobsByMetadata is ListJobs yielding only the jobs whose metadata
+// matches selector. The API cannot filter on metadata, so every job matching
+// filter is still fetched.
+func (c *Client) ListJobsByMetadata(ctx context.Context, filter *GetJobsRequest, selector Metadata) iter.Seq2[Job, error] {
+	return func(yield func(Job, error) bool) {
+		for job, err := range c.ListJobs(ctx, filter) {
+			if err != nil {
+				yield(Job{}, err)
+				return
+			}
+
+			if job.Metadata.Matches(selector) && !yield(job, nil) {
+				return
+			}
+		}
+	}
+}
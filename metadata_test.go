@@ -0,0 +1,162 @@
+package ionq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/h2non/gock"
+)
+
+func TestMetadataRoundTrip(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	metadata := Metadata(nil).WithExperiment("bell").WithOwner("alice").With("team", "chem")
+
+	newGock().
+		Post(jobsPath).
+		JSON(map[string]any{"metadata": map[string]string{"experiment": "bell", "owner": "alice", "team": "chem"}, "input": map[string]any{}}).
+		Reply(200).
+		JSON(&CreateJobResponse{ID: "some-id", Status: JobStatusReady})
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(200).
+		BodyString(`{"id": "some-id", "status": "completed", "metadata": {"experiment": "bell", "owner": "alice", "team": "chem"}}`)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	if _, err := client.CreateJob(ctx, &CreateJobRequest{Metadata: metadata}); err != nil {
+		t.Fatal(err)
+	}
+
+	jobWithStatus, err := client.GetJob(ctx, &GetJobRequest{ID: "some-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(metadata, jobWithStatus.Response.Metadata); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if jobWithStatus.Response.Metadata.Experiment() != "bell" || jobWithStatus.Response.Metadata.Owner() != "alice" {
+		t.Fatalf("unexpected metadata: %v", jobWithStatus.Response.Metadata)
+	}
+}
+
+func TestMetadataLenientDecoding(t *testing.T) {
+	var job Job
+	if err := json.Unmarshal([]byte(`{"metadata": {"commit": "abc123", "shots": 100, "debug": true, "empty": null}}`), &job); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Metadata{"commit": "abc123", "shots": "100", "debug": "true"}
+	if diff := deep.Equal(expected, job.Metadata); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if job.Metadata.Commit() != "abc123" {
+		t.Fatalf("unexpected commit: %s", job.Metadata.Commit())
+	}
+
+	if err := json.Unmarshal([]byte(`{"metadata": null}`), &job); err != nil || job.Metadata != nil {
+		t.Fatalf("unexpected metadata %v, error %v", job.Metadata, err)
+	}
+}
+
+func TestMetadataWithDoesNotModify(t *testing.T) {
+	base := Metadata{"owner": "alice"}
+	tagged := base.WithCommit("abc123")
+
+	if _, ok := base[MetadataCommit]; ok {
+		t.Fatal("expected With to copy the metadata")
+	}
+
+	if tagged.Owner() != "alice" || tagged.Commit() != "abc123" {
+		t.Fatalf("unexpected metadata: %v", tagged)
+	}
+}
+
+func TestMetadataValidate(t *testing.T) {
+	tooMany := make(Metadata)
+	for i := range MaxMetadataKeys + 1 {
+		tooMany[fmt.Sprintf("key-%d", i)] = "value"
+	}
+
+	for _, tc := range []struct {
+		name     string
+		metadata Metadata
+		valid    bool
+	}{
+		{"nil", nil, true},
+		{"tags", Metadata{"owner": "alice"}, true},
+		{"too many keys", tooMany, false},
+		{"empty key", Metadata{"": "value"}, false},
+		{"long key", Metadata{strings.Repeat("k", MaxMetadataKeyLength+1): "value"}, false},
+		{"long value", Metadata{"key": strings.Repeat("v", MaxMetadataValueLength+1)}, false},
+	} {
+		err := tc.metadata.Validate()
+		if tc.valid != (err == nil) || (err != nil && !errors.Is(err, ErrInvalidMetadata)) {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestFilterJobsByMetadata(t *testing.T) {
+	jobs := []Job{
+		{ID: "1", Metadata: Metadata{"experiment": "bell", "owner": "alice"}},
+		{ID: "2", Metadata: Metadata{"experiment": "bell", "owner": "bob"}},
+		{ID: "3"},
+	}
+
+	filtered := FilterJobsByMetadata(jobs, Metadata{"experiment": "bell", "owner": "bob"})
+	if len(filtered) != 1 || filtered[0].ID != "2" {
+		t.Fatalf("unexpected jobs: %+v", filtered)
+	}
+
+	if all := FilterJobsByMetadata(jobs, nil); len(all) != 3 {
+		t.Fatalf("expected an empty selector to match every job, received %+v", all)
+	}
+}
+
+func TestListJobsByMetadata(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(jobsPath).
+		Reply(200).
+		JSON(&GetJobsResponse{
+			Jobs: []Job{{ID: "1", Metadata: Metadata{"owner": "alice"}}, {ID: "2"}},
+			Next: "2",
+		})
+
+	newGock().
+		Get(jobsPath).
+		MatchParam("next", "2").
+		Reply(200).
+		JSON(&GetJobsResponse{Jobs: []Job{{ID: "3", Metadata: Metadata{"owner": "alice"}}}})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+
+	var ids []string
+	for job, err := range client.ListJobsByMetadata(ctx, &GetJobsRequest{}, Metadata{}.WithOwner("alice")) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, job.ID)
+	}
+
+	if diff := deep.Equal([]string{"1", "3"}, ids); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
@@ -0,0 +1,144 @@
+package ionq
+
+import (
+	"fmt"
+	"math"
+)
+
+// GatesetNative is the native gate set of IonQ's trapped ion hardware, with
+// parameters in turns.
+const GatesetNative = "native"
+
+// Gates of the native gate set.
+const (
+	GateGPI  = "gpi"
+	GateGPI2 = "gpi2"
+	GateMS   = "ms"
+	GateZZ   = "zz"
+)
+
+// MaxNativeAngle is the largest angle, in turns, of the MS and ZZ gates. An
+// MS gate with this angle is fully entangling.
+const MaxNativeAngle = 0.25
+
+// NativeCircuit builds the JobInput of a circuit using the native gate set.
+// It behaves like Circuit: calls can be chained and the first invalid gate is
+// reported by Build.
+type NativeCircuit struct {
+	gateList
+}
+
+// NewNativeCircuit returns a NativeCircuit acting on the given number of
+// qubits.
+func NewNativeCircuit(qubits uint) *NativeCircuit {
+	return &NativeCircuit{newGateList(qubits)}
+}
+
+func floatRef(f float64) *float64 {
+	return &f
+}
+
+// Gate appends a raw native gate to the circuit after validating it.
+func (c *NativeCircuit) Gate(gate CircuitInput) *NativeCircuit {
+	c.add(gate, validateNativeGate)
+	return c
+}
+
+// GPI applies a GPI gate with the given phase, in turns.
+func (c *NativeCircuit) GPI(target uint, phase float64) *NativeCircuit {
+	return c.Gate(CircuitInput{Gate: GateGPI, Target: qubitRef(target), Phase: floatRef(phase)})
+}
+
+// GPI2 applies a GPI2 gate with the given phase, in turns.
+func (c *NativeCircuit) GPI2(target uint, phase float64) *NativeCircuit {
+	return c.Gate(CircuitInput{Gate: GateGPI2, Target: qubitRef(target), Phase: floatRef(phase)})
+}
+
+// MS applies a fully entangling Mølmer-Sørensen gate with a phase, in turns,
+// for each qubit.
+func (c *NativeCircuit) MS(q0, q1 uint, phase0, phase1 float64) *NativeCircuit {
+	return c.Gate(CircuitInput{Gate: GateMS, Targets: []uint{q0, q1}, Phases: []float64{phase0, phase1}})
+}
+
+// PartialMS applies a Mølmer-Sørensen gate with the given angle, in turns,
+// between 0 and MaxNativeAngle.
+func (c *NativeCircuit) PartialMS(q0, q1 uint, phase0, phase1, angle float64) *NativeCircuit {
+	return c.Gate(CircuitInput{Gate: GateMS, Targets: []uint{q0, q1}, Phases: []float64{phase0, phase1}, Angle: floatRef(angle)})
+}
+
+// ZZ applies a ZZ gate with the given angle, in turns, between 0 and
+// MaxNativeAngle.
+func (c *NativeCircuit) ZZ(q0, q1 uint, angle float64) *NativeCircuit {
+	return c.Gate(CircuitInput{Gate: GateZZ, Targets: []uint{q0, q1}, Angle: floatRef(angle)})
+}
+
+// Build returns the JobInput of the circuit, or the first error encountered
+// while building it.
+func (c *NativeCircuit) Build() (JobInput, error) {
+	return c.build(GatesetNative)
+}
+
+func validatePhase(gate string, phase float64) error {
+	if math.IsNaN(phase) || phase < 0 || phase > 1 {
+		return fmt.Errorf("%w: %s phase %v must be between 0 and 1 turn", ErrInvalidCircuit, gate, phase)
+	}
+	return nil
+}
+
+func validateAngle(gate string, angle float64) error {
+	if math.IsNaN(angle) || angle < 0 || angle > MaxNativeAngle {
+		return fmt.Errorf("%w: %s angle %v must be between 0 and %v turns", ErrInvalidCircuit, gate, angle, MaxNativeAngle)
+	}
+	return nil
+}
+
+func validateNativeGate(gate CircuitInput, qubits uint) error {
+	if err := validateGateQubits(gate, qubits); err != nil {
+		return err
+	}
+
+	if gate.Control != nil || len(gate.Controls) > 0 {
+		return fmt.Errorf("%w: native gate %s cannot be controlled", ErrInvalidCircuit, gate.Gate)
+	}
+
+	if gate.Rotation != 0 {
+		return fmt.Errorf("%w: native gate %s cannot have a rotation", ErrInvalidCircuit, gate.Gate)
+	}
+
+	switch gate.Gate {
+	case GateGPI, GateGPI2:
+		if gate.Target == nil || len(gate.Targets) > 0 {
+			return fmt.Errorf("%w: %s requires a single target", ErrInvalidCircuit, gate.Gate)
+		}
+		if gate.Phase == nil || len(gate.Phases) > 0 || gate.Angle != nil {
+			return fmt.Errorf("%w: %s requires a phase only", ErrInvalidCircuit, gate.Gate)
+		}
+		return validatePhase(gate.Gate, *gate.Phase)
+	case GateMS:
+		if gate.Target != nil || len(gate.Targets) != 2 {
+			return fmt.Errorf("%w: ms requires two targets", ErrInvalidCircuit)
+		}
+		if gate.Phase != nil || len(gate.Phases) != 2 {
+			return fmt.Errorf("%w: ms requires two phases", ErrInvalidCircuit)
+		}
+		for _, phase := range gate.Phases {
+			if err := validatePhase(gate.Gate, phase); err != nil {
+				return err
+			}
+		}
+		if gate.Angle != nil {
+			return validateAngle(gate.Gate, *gate.Angle)
+		}
+		return nil
+	case GateZZ:
+		if gate.Target != nil || len(gate.Targets) != 2 {
+			return fmt.Errorf("%w: zz requires two targets", ErrInvalidCircuit)
+		}
+		if gate.Angle == nil || gate.Phase != nil || len(gate.Phases) > 0 {
+			return fmt.Errorf("%w: zz requires an angle only", ErrInvalidCircuit)
+		}
+		return validateAngle(gate.Gate, *gate.Angle)
+	}
+
+	return fmt.Errorf("%w: unsupported native gate %q", ErrInvalidCircuit, gate.Gate)
+}
@@ -0,0 +1,68 @@
+package ionq
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNativeCircuitBuild(t *testing.T) {
+	input, err := NewNativeCircuit(2).
+		GPI(0, 0).
+		GPI2(1, 0.25).
+		MS(0, 1, 0, 0.5).
+		PartialMS(0, 1, 0.1, 0.2, 0.125).
+		ZZ(1, 0, 0.05).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if input.Gateset != GatesetNative || input.Qubits != 2 || input.Format != CircuitFormat {
+		t.Fatalf("unexpected input: %+v", input)
+	}
+
+	body, err := json.Marshal(input.Circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[` +
+		`{"gate":"gpi","target":0,"phase":0},` +
+		`{"gate":"gpi2","target":1,"phase":0.25},` +
+		`{"gate":"ms","targets":[0,1],"phases":[0,0.5]},` +
+		`{"gate":"ms","targets":[0,1],"phases":[0.1,0.2],"angle":0.125},` +
+		`{"gate":"zz","targets":[1,0],"angle":0.05}` +
+		`]`
+	if string(body) != expected {
+		t.Fatalf("unexpected circuit:\n%s\nexpected:\n%s", body, expected)
+	}
+}
+
+func TestNativeCircuitValidation(t *testing.T) {
+	for name, c := range map[string]*NativeCircuit{
+		"phase range":    NewNativeCircuit(1).GPI(0, 1.5),
+		"negative phase": NewNativeCircuit(1).GPI2(0, -0.1),
+		"ms phase range": NewNativeCircuit(2).MS(0, 1, 0, 2),
+		"ms angle range": NewNativeCircuit(2).PartialMS(0, 1, 0, 0, 0.3),
+		"zz angle range": NewNativeCircuit(2).ZZ(0, 1, -0.01),
+		"same qubit":     NewNativeCircuit(2).MS(1, 1, 0, 0),
+		"target range":   NewNativeCircuit(2).GPI(2, 0),
+		"qis gate":       NewNativeCircuit(1).Gate(CircuitInput{Gate: GateH, Target: qubitRef(0)}),
+		"missing phase":  NewNativeCircuit(1).Gate(CircuitInput{Gate: GateGPI, Target: qubitRef(0)}),
+		"controlled": NewNativeCircuit(2).Gate(CircuitInput{
+			Gate: GateGPI, Target: qubitRef(0), Control: qubitRef(1), Phase: floatRef(0),
+		}),
+	} {
+		if _, err := c.Build(); !errors.Is(err, ErrInvalidCircuit) {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+	}
+}
+
+func TestCircuitRejectsNativeParameters(t *testing.T) {
+	_, err := NewCircuit(1).Gate(CircuitInput{Gate: GateX, Target: qubitRef(0), Phase: floatRef(0.5)}).Build()
+	if !errors.Is(err, ErrInvalidCircuit) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package ionq
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client created with NewClient.
+type Option func(*clientConfig)
+
+// WithHTTPClient sets the http.Client used to send requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(cfg *clientConfig) {
+		cfg.client = client
+	}
+}
+
+// WithBaseURL overrides the endpoint passed to NewClient, for example to
+// point the Client at a proxy or a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(cfg *clientConfig) {
+		cfg.endpoint = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(cfg *clientConfig) {
+		cfg.userAgent = userAgent
+	}
+}
+
+// WithTimeout sets the timeout of the underlying http.Client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cfg *clientConfig) {
+		cfg.timeout = timeout
+		cfg.hasTimeout = true
+	}
+}
+
+// WithTransport sets the http.RoundTripper of the underlying http.Client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(cfg *clientConfig) {
+		cfg.transport = transport
+	}
+}
@@ -0,0 +1,109 @@
+package ionq
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewClientDefaultEndpoint(t *testing.T) {
+	client := NewClient("", myFakeAPIKey)
+
+	if client.endpoint != DefaultEndpoint {
+		t.Fatalf("unexpected endpoint: %s", client.endpoint)
+	}
+
+	if client.userAgent != defaultUserAgent {
+		t.Fatalf("unexpected user agent: %s", client.userAgent)
+	}
+}
+
+func TestWithBaseURLAndUserAgent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get("jobs/some-id").
+		MatchHeader("User-Agent", "my-agent/1.0").
+		Reply(200).
+		JSON(&GetJobResponse{ID: "some-id"})
+
+	client := NewClient("https://not-used.test/v0.3", myFakeAPIKey,
+		WithBaseURL(myFakeEndpoint+"/"),
+		WithUserAgent("my-agent/1.0"),
+	)
+
+	jobResponseWithStatus, err := client.GetJob(ctx, &GetJobRequest{ID: "some-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if jobResponseWithStatus.Response.ID != "some-id" {
+		t.Fatalf("unexpected id: %s", jobResponseWithStatus.Response.ID)
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var requestedURL string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requestedURL = req.URL.String()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{"id":"some-id","status":"canceled"}`)),
+		}, nil
+	})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithTransport(transport))
+
+	cancelJobResponseWithStatus, err := client.CancelJob(ctx, &CancelJobRequest{ID: "some-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requestedURL != myFakeEndpoint+"/jobs/some-id/status/cancel" {
+		t.Fatalf("unexpected url: %s", requestedURL)
+	}
+
+	if cancelJobResponseWithStatus.Response.Status != "canceled" {
+		t.Fatalf("unexpected status: %s", cancelJobResponseWithStatus.Response.Status)
+	}
+}
+
+func TestWithTimeoutDoesNotModifyHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey,
+		WithTimeout(3*time.Second),
+		WithHTTPClient(httpClient),
+	)
+
+	if client.client.Timeout != 3*time.Second {
+		t.Fatalf("unexpected timeout: %s", client.client.Timeout)
+	}
+
+	if httpClient.Timeout != 0 {
+		t.Fatalf("provided http.Client was modified")
+	}
+
+	client = NewClient(myFakeEndpoint, myFakeAPIKey, WithHTTPClient(httpClient))
+	if client.client != httpClient {
+		t.Fatalf("expected provided http.Client to be used")
+	}
+}
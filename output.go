@@ -0,0 +1,140 @@
+package ionq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// OutputEntry is the probability of one basis state of a job output, keyed
+// by its decimal index as in GetJobOutputResponse.
+type OutputEntry struct {
+	State       string
+	Probability float32
+}
+
+// DecodeJobOutput decodes a job output read from r one basis state at a
+// time, so large outputs are never held in memory as a whole. Decoding stops
+// at the first error.
+func DecodeJobOutput(r io.Reader) iter.Seq2[OutputEntry, error] {
+	return func(yield func(OutputEntry, error) bool) {
+		dec := json.NewDecoder(r)
+
+		token, err := dec.Token()
+		if err != nil {
+			yield(OutputEntry{}, err)
+			return
+		}
+		if token != json.Delim('{') {
+			yield(OutputEntry{}, fmt.Errorf("ionq: job output must be an object, got %v", token))
+			return
+		}
+
+		for dec.More() {
+			token, err := dec.Token()
+			if err != nil {
+				yield(OutputEntry{}, err)
+				return
+			}
+			state := token.(string) // object keys are always strings
+
+			token, err = dec.Token()
+			if err != nil {
+				yield(OutputEntry{}, err)
+				return
+			}
+			probability, ok := token.(float64)
+			if !ok {
+				yield(OutputEntry{}, fmt.Errorf("ionq: probability of basis state %q is not a number", state))
+				return
+			}
+
+			if !yield(OutputEntry{State: state, Probability: float32(probability)}, nil) {
+				return
+			}
+		}
+
+		if _, err := dec.Token(); err != nil {
+			yield(OutputEntry{}, err)
+		}
+	}
+}
+
+// StreamJobOutput retrieves the output of a job like GetJobOutput, decoding
+// the response body as it arrives with DecodeJobOutput. The response is
+// closed when iteration stops.
+func (c *Client) StreamJobOutput(ctx context.Context, getJobOutputRequest *GetJobOutputRequest) iter.Seq2[OutputEntry, error] {
+	return func(yield func(OutputEntry, error) bool) {
+		url, err := c.jobOutputURL(getJobOutputRequest)
+		if err != nil {
+			yield(OutputEntry{}, err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			yield(OutputEntry{}, err)
+			return
+		}
+
+		c.setHeaders(req)
+
+		body, _, err := c.doStream(req)
+		if err != nil {
+			yield(OutputEntry{}, err)
+			return
+		}
+		defer body.Close()
+
+		for entry, err := range DecodeJobOutput(body) {
+			if !yield(entry, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// JobOutputComparison holds the output of a job in both aggregations, to
+// compare the effect of sharpening.
+//
+// It is not a comparison of raw and mitigated results: the API cannot return
+// the output of a debiased job without error mitigation after the fact, so
+// both outputs are mitigated for such jobs. Averaged is the closest there is
+// to a raw distribution; running the circuit again without Debias is the only
+// way to obtain an unmitigated one.
+type JobOutputComparison struct {
+	// Averaged averages the results of the symmetrized variants of the
+	// circuit, the default aggregation.
+	Averaged GetJobOutputResponse
+
+	// Sharpened aggregates the variants by plurality vote.
+	Sharpened GetJobOutputResponse
+}
+
+// CompareJobOutputs retrieves the output of a job both averaged and
+// sharpened. The Sharpen field of the request is ignored.
+func (c *Client) CompareJobOutputs(ctx context.Context, getJobOutputRequest *GetJobOutputRequest) (*JobOutputComparison, error) {
+	averagedRequest := *getJobOutputRequest
+	averagedRequest.Sharpen = false
+
+	averaged, err := c.GetJobOutput(ctx, &averagedRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	sharpenedRequest := *getJobOutputRequest
+	sharpenedRequest.Sharpen = true
+
+	sharpened, err := c.GetJobOutput(ctx, &sharpenedRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JobOutputComparison{
+		Averaged:  averaged.Response,
+		Sharpened: sharpened.Response,
+	}, nil
+}
@@ -0,0 +1,140 @@
+package ionq
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/h2non/gock"
+)
+
+func collectOutput(t *testing.T, entries func(func(OutputEntry, error) bool)) (GetJobOutputResponse, error) {
+	t.Helper()
+
+	output := make(GetJobOutputResponse)
+	for entry, err := range entries {
+		if err != nil {
+			return output, err
+		}
+		output[entry.State] = entry.Probability
+	}
+	return output, nil
+}
+
+func TestStreamJobOutputSuccess(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id/results", jobsPath)).
+		Reply(200).
+		BodyString(`{"0": 0.2, "1": 0.4, "3": 0.4}`)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	output, err := collectOutput(t, client.StreamJobOutput(ctx, &GetJobOutputRequest{ID: "some-id"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := GetJobOutputResponse{"0": 0.2, "1": 0.4, "3": 0.4}
+	if diff := deep.Equal(expected, output); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestStreamJobOutputStopsEarly(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id/results", jobsPath)).
+		Reply(200).
+		BodyString(`{"0": 0.5, "1": 0.5, "not valid`)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+
+	// the truncated end of the body is never read
+	for entry, err := range client.StreamJobOutput(ctx, &GetJobOutputRequest{ID: "some-id"}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.State != "0" {
+			t.Fatalf("unexpected entry: %+v", entry)
+		}
+		break
+	}
+}
+
+func TestStreamJobOutputAPIError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id/results", jobsPath)).
+		Reply(404).
+		JSON(map[string]string{"error": "Not Found", "message": "job not found"})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := collectOutput(t, client.StreamJobOutput(ctx, &GetJobOutputRequest{ID: "some-id"}))
+	assertAPIError(t, err, http.StatusNotFound, ErrNotFound)
+}
+
+func TestDecodeJobOutputErrors(t *testing.T) {
+	for name, body := range map[string]string{
+		"not an object":   `[0.5, 0.5]`,
+		"nested output":   `{"child": {"0": 1}}`,
+		"string value":    `{"0": "1"}`,
+		"truncated":       `{"0": 0.5, "1"`,
+		"missing closing": `{"0": 1`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := collectOutput(t, DecodeJobOutput(strings.NewReader(body))); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestCompareJobOutputs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	averaged := GetJobOutputResponse{"0": 0.45, "1": 0.1, "3": 0.45}
+	sharpened := GetJobOutputResponse{"0": 0.5, "3": 0.5}
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id/results", jobsPath)).
+		MatchParam("sharpen", "true").
+		MatchParam("precision", "2").
+		Reply(200).
+		JSON(&sharpened)
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id/results", jobsPath)).
+		MatchParam("precision", "2").
+		Reply(200).
+		JSON(&averaged)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	comparison, err := client.CompareJobOutputs(ctx, &GetJobOutputRequest{ID: "some-id", Sharpen: true, Precision: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &JobOutputComparison{Averaged: averaged, Sharpened: sharpened}
+	if diff := deep.Equal(expected, comparison); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
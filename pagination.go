@@ -0,0 +1,67 @@
+package ionq
+
+import (
+	"context"
+	"iter"
+)
+
+// ListJobs returns an iterator over all the jobs matching filter, following
+// the next cursor returned by GetJobs. filter.Limit is used as the page size
+// and filter.Next, when set, as the starting cursor. The iteration stops after
+// yielding the first error, including the cancellation of ctx.
+func (c *Client) ListJobs(ctx context.Context, filter *GetJobsRequest) iter.Seq2[Job, error] {
+	return func(yield func(Job, error) bool) {
+		var getJobsRequest GetJobsRequest
+		if filter != nil {
+			getJobsRequest = *filter
+		}
+
+		seen := make(map[string]bool)
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(Job{}, err)
+				return
+			}
+
+			jobsResponseWithStatus, err := c.GetJobs(ctx, &getJobsRequest)
+			if err != nil {
+				yield(Job{}, err)
+				return
+			}
+
+			for _, job := range jobsResponseWithStatus.Response.Jobs {
+				if !yield(job, nil) {
+					return
+				}
+			}
+
+			next := jobsResponseWithStatus.Response.Next
+			if next == "" || len(jobsResponseWithStatus.Response.Jobs) == 0 || seen[next] {
+				return
+			}
+
+			seen[next] = true
+			getJobsRequest.Next = next
+		}
+	}
+}
+
+// CollectJobs returns the jobs yielded by ListJobs, stopping once maxJobs jobs
+// have been collected. A maxJobs of 0 or less collects every job.
+func (c *Client) CollectJobs(ctx context.Context, filter *GetJobsRequest, maxJobs int) ([]Job, error) {
+	var jobs []Job
+
+	for job, err := range c.ListJobs(ctx, filter) {
+		if err != nil {
+			return jobs, err
+		}
+
+		jobs = append(jobs, job)
+		if maxJobs > 0 && len(jobs) >= maxJobs {
+			break
+		}
+	}
+
+	return jobs, nil
+}
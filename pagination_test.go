@@ -0,0 +1,136 @@
+package ionq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/h2non/gock"
+)
+
+func mockJobPages(t *testing.T) []Job {
+	t.Helper()
+
+	pages := []GetJobsResponse{
+		{
+			Jobs: []Job{{ID: "job-1"}, {ID: "job-2"}},
+			Next: "job-2",
+		},
+		{
+			Jobs: []Job{{ID: "job-3"}, {ID: "job-4"}},
+			Next: "job-4",
+		},
+		{
+			Jobs: []Job{{ID: "job-5"}},
+		},
+	}
+
+	next := ""
+	var jobs []Job
+	for _, page := range pages {
+		newGock().
+			Get(jobsPath).
+			MatchParams(map[string]string{
+				"limit": "2",
+				"next":  next,
+			}).
+			Reply(200).
+			JSON(&page)
+
+		next = page.Next
+		jobs = append(jobs, page.Jobs...)
+	}
+
+	return jobs
+}
+
+func TestListJobsFollowsNext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	expectedJobs := mockJobPages(t)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+
+	var jobs []Job
+	for job, err := range client.ListJobs(ctx, &GetJobsRequest{Limit: 2}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if diff := deep.Equal(expectedJobs, jobs); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if !gock.IsDone() {
+		t.Fatal("expected every page to be requested")
+	}
+}
+
+func TestCollectJobsMax(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	expectedJobs := mockJobPages(t)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	jobs, err := client.CollectJobs(ctx, &GetJobsRequest{Limit: 2}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expectedJobs[:3], jobs); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if gock.IsDone() {
+		t.Fatal("expected the last page not to be requested")
+	}
+}
+
+func TestListJobsError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(jobsPath).
+		Reply(200).
+		JSON(&GetJobsResponse{
+			Jobs: []Job{{ID: "job-1"}},
+			Next: "job-1",
+		})
+
+	newGock().
+		Get(jobsPath).
+		Reply(500)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	jobs, err := client.CollectJobs(ctx, nil, 0)
+	assertAPIError(t, err, http.StatusInternalServerError, ErrServer)
+
+	if len(jobs) != 1 {
+		t.Fatalf("unexpected number of jobs: %d", len(jobs))
+	}
+}
+
+func TestListJobsContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := client.CollectJobs(ctx, nil, 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package qasm
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+func TestRoundTrip(t *testing.T) {
+	circuits := map[string]interface {
+		Build() (ionq.JobInput, error)
+	}{
+		"qis": ionq.NewCircuit(4).
+			H(0).CNOT(0, 1).X(2).Y(3).Z(0).S(1).Si(2).T(3).Ti(0).V(1).Vi(2).
+			RX(0, math.Pi/3).RY(1, -0.25).RZ(2, 1e-5).
+			Toffoli(0, 1, 3).
+			Swap(2, 3).
+			ControlledRotation(ionq.GateRY, []uint{1, 2, 3}, 0, 0.75).
+			Controlled(ionq.GateZ, []uint{0, 1}, 2),
+		"native": ionq.NewNativeCircuit(3).
+			GPI(0, 0.125).GPI2(1, 0.9).
+			MS(0, 2, 0.1, 0.2).
+			PartialMS(1, 2, 0, 0.5, 0.05).
+			ZZ(2, 0, 0.2),
+	}
+
+	for name, circuit := range circuits {
+		input, err := circuit.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		program, err := input.ToQASM(ionq.QASM3)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		parsed, err := Parse(program)
+		if err != nil {
+			t.Fatalf("%s: %s\n%s", name, err, program)
+		}
+
+		if diff := deep.Equal(input, parsed); len(diff) > 0 {
+			t.Fatalf("%s: unexpected diff: %s\n%s", name, diff, program)
+		}
+	}
+}
+
+func TestRoundTripQASM2(t *testing.T) {
+	input, err := ionq.NewCircuit(3).H(0).CNOT(0, 1).Toffoli(0, 1, 2).RZ(2, math.Pi).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program, err := input.ToQASM(ionq.QASM2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := Parse(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(input, parsed); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
@@ -0,0 +1,327 @@
+package qasm
+
+import (
+	"fmt"
+	"math"
+
+	"ionq"
+)
+
+type builtinGate struct {
+	params int
+	qubits int
+
+	// optionalParam is set when the last parameter may be left out
+	optionalParam bool
+
+	convert func(params []float64, qubits []uint) []ionq.CircuitInput
+}
+
+func qubitRef(q uint) *uint {
+	return &q
+}
+
+func floatRef(f float64) *float64 {
+	return &f
+}
+
+// normalizePhase brings a phase in turns between 0 and 1, which does not
+// change the gate since phases are periodic.
+func normalizePhase(phase float64) float64 {
+	phase = math.Mod(phase, 1)
+	if phase < 0 {
+		phase++
+	}
+	return phase
+}
+
+func single(gate string) builtinGate {
+	return builtinGate{
+		qubits: 1,
+		convert: func(_ []float64, qubits []uint) []ionq.CircuitInput {
+			return []ionq.CircuitInput{{Gate: gate, Target: qubitRef(qubits[0])}}
+		},
+	}
+}
+
+func rotation(gate string) builtinGate {
+	return builtinGate{
+		params: 1,
+		qubits: 1,
+		convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+			return []ionq.CircuitInput{{Gate: gate, Target: qubitRef(qubits[0]), Rotation: params[0]}}
+		},
+	}
+}
+
+func controlled(gate string, controls int) builtinGate {
+	return builtinGate{
+		qubits: controls + 1,
+		convert: func(_ []float64, qubits []uint) []ionq.CircuitInput {
+			return []ionq.CircuitInput{controlledGate(gate, qubits, 0)}
+		},
+	}
+}
+
+func controlledRotation(gate string) builtinGate {
+	return builtinGate{
+		params: 1,
+		qubits: 2,
+		convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+			return []ionq.CircuitInput{controlledGate(gate, qubits, params[0])}
+		},
+	}
+}
+
+// controlledGate uses the last qubit as the target and the others as
+// controls.
+func controlledGate(gate string, qubits []uint, rotation float64) ionq.CircuitInput {
+	target := qubits[len(qubits)-1]
+	controls := qubits[:len(qubits)-1]
+
+	input := ionq.CircuitInput{Gate: gate, Target: qubitRef(target), Rotation: rotation}
+	if len(controls) == 1 {
+		input.Control = qubitRef(controls[0])
+	} else {
+		input.Controls = append([]uint(nil), controls...)
+	}
+	return input
+}
+
+// angleEpsilon is the tolerance under which a rotation angle is zero.
+const angleEpsilon = 1e-12
+
+// isZeroRotation reports whether a rotation of angle radians is the identity
+// up to a global phase, that is whether angle is a multiple of 2π.
+func isZeroRotation(angle float64) bool {
+	return math.Abs(math.Remainder(angle, 2*math.Pi)) < angleEpsilon
+}
+
+// u converts U(θ, φ, λ), equal to RZ(φ)·RY(θ)·RZ(λ) up to a global phase.
+// Rotations of a zero angle are left out.
+func u(theta, phi, lambda float64, target uint) []ionq.CircuitInput {
+	var converted []ionq.CircuitInput
+	for _, gate := range []ionq.CircuitInput{
+		{Gate: ionq.GateRZ, Target: qubitRef(target), Rotation: lambda},
+		{Gate: ionq.GateRY, Target: qubitRef(target), Rotation: theta},
+		{Gate: ionq.GateRZ, Target: qubitRef(target), Rotation: phi},
+	} {
+		if !isZeroRotation(gate.Rotation) {
+			converted = append(converted, gate)
+		}
+	}
+	return converted
+}
+
+var u3 = builtinGate{
+	params: 3,
+	qubits: 1,
+	convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+		return u(params[0], params[1], params[2], qubits[0])
+	},
+}
+
+var u2 = builtinGate{
+	params: 2,
+	qubits: 1,
+	convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+		return u(math.Pi/2, params[0], params[1], qubits[0])
+	},
+}
+
+// controlledPhase converts CP(λ) into an RZ(λ/2) on the control followed by
+// a controlled RZ(λ), which are equal up to a global phase.
+var controlledPhase = builtinGate{
+	params: 1,
+	qubits: 2,
+	convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+		return []ionq.CircuitInput{
+			{Gate: ionq.GateRZ, Target: qubitRef(qubits[0]), Rotation: params[0] / 2},
+			controlledGate(ionq.GateRZ, qubits, params[0]),
+		}
+	},
+}
+
+var swap = builtinGate{
+	qubits: 2,
+	convert: func(_ []float64, qubits []uint) []ionq.CircuitInput {
+		return []ionq.CircuitInput{{Gate: ionq.GateSwap, Targets: []uint{qubits[0], qubits[1]}}}
+	},
+}
+
+var controlledSwap = builtinGate{
+	qubits: 3,
+	convert: func(_ []float64, qubits []uint) []ionq.CircuitInput {
+		return []ionq.CircuitInput{{Gate: ionq.GateSwap, Control: qubitRef(qubits[0]), Targets: []uint{qubits[1], qubits[2]}}}
+	},
+}
+
+// twoQubitRotation converts the rotations exp(-iθ/2 P⊗P) of the Pauli
+// operator P, given the gates mapping P to Z and back.
+func twoQubitRotation(toZ, fromZ func(target uint) []ionq.CircuitInput) builtinGate {
+	return builtinGate{
+		params: 1,
+		qubits: 2,
+		convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+			a, b := qubits[0], qubits[1]
+			var gates []ionq.CircuitInput
+			gates = append(gates, toZ(a)...)
+			gates = append(gates, toZ(b)...)
+			gates = append(gates,
+				ionq.CircuitInput{Gate: ionq.GateCNOT, Control: qubitRef(a), Target: qubitRef(b)},
+				ionq.CircuitInput{Gate: ionq.GateRZ, Target: qubitRef(b), Rotation: params[0]},
+				ionq.CircuitInput{Gate: ionq.GateCNOT, Control: qubitRef(a), Target: qubitRef(b)},
+			)
+			gates = append(gates, fromZ(a)...)
+			gates = append(gates, fromZ(b)...)
+			return gates
+		},
+	}
+}
+
+func gates(gate string, rotation float64) func(uint) []ionq.CircuitInput {
+	return func(target uint) []ionq.CircuitInput {
+		return []ionq.CircuitInput{{Gate: gate, Target: qubitRef(target), Rotation: rotation}}
+	}
+}
+
+func noGates(uint) []ionq.CircuitInput {
+	return nil
+}
+
+var identity = builtinGate{
+	qubits: 1,
+	convert: func([]float64, []uint) []ionq.CircuitInput {
+		return nil
+	},
+}
+
+// nativeGates are converted to the native gate set, with their parameters
+// in turns.
+var nativeGates = map[string]builtinGate{
+	ionq.GateGPI: {
+		params: 1,
+		qubits: 1,
+		convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+			return []ionq.CircuitInput{{Gate: ionq.GateGPI, Target: qubitRef(qubits[0]), Phase: floatRef(normalizePhase(params[0]))}}
+		},
+	},
+	ionq.GateGPI2: {
+		params: 1,
+		qubits: 1,
+		convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+			return []ionq.CircuitInput{{Gate: ionq.GateGPI2, Target: qubitRef(qubits[0]), Phase: floatRef(normalizePhase(params[0]))}}
+		},
+	},
+	ionq.GateMS: {
+		params:        3,
+		qubits:        2,
+		optionalParam: true,
+		convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+			gate := ionq.CircuitInput{
+				Gate:    ionq.GateMS,
+				Targets: []uint{qubits[0], qubits[1]},
+				Phases:  []float64{normalizePhase(params[0]), normalizePhase(params[1])},
+			}
+			if len(params) == 3 && params[2] != ionq.MaxNativeAngle {
+				gate.Angle = floatRef(params[2])
+			}
+			return []ionq.CircuitInput{gate}
+		},
+	},
+	ionq.GateZZ: {
+		params: 1,
+		qubits: 2,
+		convert: func(params []float64, qubits []uint) []ionq.CircuitInput {
+			return []ionq.CircuitInput{{Gate: ionq.GateZZ, Targets: []uint{qubits[0], qubits[1]}, Angle: floatRef(params[0])}}
+		},
+	},
+}
+
+// builtinGates maps the gates of qelib1.inc, stdgates.inc and the native
+// gates to IonQ gates.
+var builtinGates = map[string]builtinGate{
+	"id":   identity,
+	"i":    identity,
+	"x":    single(ionq.GateX),
+	"y":    single(ionq.GateY),
+	"z":    single(ionq.GateZ),
+	"h":    single(ionq.GateH),
+	"s":    single(ionq.GateS),
+	"sdg":  single(ionq.GateSi),
+	"t":    single(ionq.GateT),
+	"tdg":  single(ionq.GateTi),
+	"sx":   single(ionq.GateV),
+	"sxdg": single(ionq.GateVi),
+	"v":    single(ionq.GateV),
+	"vi":   single(ionq.GateVi),
+	"si":   single(ionq.GateSi),
+	"ti":   single(ionq.GateTi),
+	"not":  single(ionq.GateNot),
+
+	"rx":    rotation(ionq.GateRX),
+	"ry":    rotation(ionq.GateRY),
+	"rz":    rotation(ionq.GateRZ),
+	"p":     rotation(ionq.GateRZ),
+	"phase": rotation(ionq.GateRZ),
+	"u1":    rotation(ionq.GateRZ),
+	"u2":    u2,
+	"u3":    u3,
+	"u":     u3,
+	"U":     u3,
+
+	"cx":      controlled(ionq.GateCNOT, 1),
+	"CX":      controlled(ionq.GateCNOT, 1),
+	"cnot":    controlled(ionq.GateCNOT, 1),
+	"cy":      controlled(ionq.GateY, 1),
+	"cz":      controlled(ionq.GateZ, 1),
+	"ch":      controlled(ionq.GateH, 1),
+	"csx":     controlled(ionq.GateV, 1),
+	"ccx":     controlled(ionq.GateX, 2),
+	"toffoli": controlled(ionq.GateX, 2),
+	"c3x":     controlled(ionq.GateX, 3),
+	"crx":     controlledRotation(ionq.GateRX),
+	"cry":     controlledRotation(ionq.GateRY),
+	"crz":     controlledRotation(ionq.GateRZ),
+	"cp":      controlledPhase,
+	"cu1":     controlledPhase,
+	"cphase":  controlledPhase,
+	"rxx":     twoQubitRotation(gates(ionq.GateH, 0), gates(ionq.GateH, 0)),
+	"ryy":     twoQubitRotation(gates(ionq.GateRX, math.Pi/2), gates(ionq.GateRX, -math.Pi/2)),
+	"rzz":     twoQubitRotation(noGates, noGates),
+	"swap":    swap,
+	"cswap":   controlledSwap,
+	"fredkin": controlledSwap,
+}
+
+func init() {
+	for name, gate := range nativeGates {
+		builtinGates[name] = gate
+	}
+}
+
+// ConvertGate converts a gate named as in qelib1.inc and stdgates.inc, or one
+// of the native gates, into IonQ gates. Controls come first in qubits. It
+// returns the gates and the gate set they belong to; unknown gates return an
+// error wrapping ErrUnsupported.
+func ConvertGate(name string, params []float64, qubits []uint) ([]ionq.CircuitInput, string, error) {
+	builtin, ok := builtinGates[name]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: unknown gate %q", ErrUnsupported, name)
+	}
+
+	if len(params) != builtin.params && !(builtin.optionalParam && len(params) == builtin.params-1) {
+		return nil, "", fmt.Errorf("gate %q expects %d parameters, found %d", name, builtin.params, len(params))
+	}
+
+	if len(qubits) != builtin.qubits {
+		return nil, "", fmt.Errorf("gate %q expects %d qubits, found %d", name, builtin.qubits, len(qubits))
+	}
+
+	gateset := ionq.GatesetQIS
+	if _, native := nativeGates[name]; native {
+		gateset = ionq.GatesetNative
+	}
+
+	return builtin.convert(params, qubits), gateset, nil
+}
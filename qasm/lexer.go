@@ -0,0 +1,137 @@
+package qasm
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenSymbol
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	line   int
+	column int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of input"
+	}
+	return "\"" + t.text + "\""
+}
+
+// twoCharSymbols are the symbols made of two characters, the others are a
+// single character.
+var twoCharSymbols = []string{"->", "==", "**"}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	line, column := 1, 1
+
+	advance := func(n int) {
+		for _, r := range src[:n] {
+			if r == '\n' {
+				line++
+				column = 1
+			} else {
+				column++
+			}
+		}
+		src = src[n:]
+	}
+
+	for len(src) > 0 {
+		r, size := utf8.DecodeRuneInString(src)
+
+		switch {
+		case unicode.IsSpace(r):
+			advance(size)
+		case strings.HasPrefix(src, "//"):
+			end := strings.IndexByte(src, '\n')
+			if end < 0 {
+				end = len(src)
+			}
+			advance(end)
+		case strings.HasPrefix(src, "/*"):
+			end := strings.Index(src, "*/")
+			if end < 0 {
+				return nil, errorf(line, column, "unterminated comment")
+			}
+			advance(end + 2)
+		case r == '"':
+			end := strings.IndexByte(src[1:], '"')
+			if end < 0 {
+				return nil, errorf(line, column, "unterminated string")
+			}
+			tokens = append(tokens, token{tokenString, src[1 : end+1], line, column})
+			advance(end + 2)
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			end := strings.IndexFunc(src, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$'
+			})
+			if end < 0 {
+				end = len(src)
+			}
+			tokens = append(tokens, token{tokenIdent, src[:end], line, column})
+			advance(end)
+		case unicode.IsDigit(r) || (r == '.' && len(src) > 1 && src[1] >= '0' && src[1] <= '9'):
+			end := numberEnd(src)
+			tokens = append(tokens, token{tokenNumber, src[:end], line, column})
+			advance(end)
+		default:
+			n := size
+			for _, symbol := range twoCharSymbols {
+				if strings.HasPrefix(src, symbol) {
+					n = len(symbol)
+				}
+			}
+			if n == size && !strings.ContainsRune(";,()[]{}+-*/^=@<>!", r) {
+				return nil, errorf(line, column, "unexpected character %q", r)
+			}
+			tokens = append(tokens, token{tokenSymbol, src[:n], line, column})
+			advance(n)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, line: line, column: column}), nil
+}
+
+// numberEnd returns the length of the number at the start of src, including
+// a fraction and an exponent.
+func numberEnd(src string) int {
+	i := 0
+	digits := func() {
+		for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '_') {
+			i++
+		}
+	}
+
+	digits()
+	if i < len(src) && src[i] == '.' {
+		i++
+		digits()
+	}
+
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		j := i + 1
+		if j < len(src) && (src[j] == '+' || src[j] == '-') {
+			j++
+		}
+		if j < len(src) && src[j] >= '0' && src[j] <= '9' {
+			i = j
+			digits()
+		}
+	}
+
+	return i
+}
@@ -0,0 +1,908 @@
+package qasm
+
+import (
+	"math"
+	"slices"
+	"strconv"
+
+	"ionq"
+)
+
+// maxGateDepth limits the nesting of user defined gates.
+const maxGateDepth = 64
+
+var supportedIncludes = []string{"qelib1.inc", "stdgates.inc"}
+
+// unsupportedKeywords are OpenQASM statements with no IonQ equivalent.
+var unsupportedKeywords = []string{
+	"reset", "if", "else", "for", "while", "def", "defcal", "cal", "box",
+	"negctrl", "inv", "pow", "input", "output", "extern", "let",
+	"const", "int", "uint", "float", "angle", "bool", "complex", "duration",
+	"stretch", "delay", "return", "break", "continue", "end", "gphase",
+}
+
+type register struct {
+	start uint
+	size  uint
+}
+
+type gateDef struct {
+	params []string
+	qargs  []string
+	body   []token
+	opaque bool
+}
+
+// scope holds the parameters and qubit arguments of a user defined gate
+// while its body is expanded.
+type scope struct {
+	params map[string]float64
+	qargs  map[string]uint
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+
+	qubits    uint
+	registers map[string]register
+	bits      map[string]bool
+	gates     map[string]*gateDef
+	measured  map[uint]bool
+
+	gateset string
+	circuit []ionq.CircuitInput
+}
+
+func newParser(tokens []token) *parser {
+	return &parser{
+		tokens:    tokens,
+		registers: make(map[string]register),
+		bits:      make(map[string]bool),
+		gates:     make(map[string]*gateDef),
+		measured:  make(map[uint]bool),
+	}
+}
+
+func (p *parser) input() ionq.JobInput {
+	gateset := p.gateset
+	if gateset == "" {
+		gateset = ionq.GatesetQIS
+	}
+
+	return ionq.JobInput{
+		Format:  ionq.CircuitFormat,
+		Gateset: gateset,
+		Qubits:  p.qubits,
+		Circuit: p.circuit,
+	}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokenSymbol || t.kind == tokenIdent) && t.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) (token, error) {
+	t := p.next()
+	if (t.kind != tokenSymbol && t.kind != tokenIdent) || t.text != text {
+		return t, errorf(t.line, t.column, "expected %q, found %s", text, t)
+	}
+	return t, nil
+}
+
+func (p *parser) expectIdent() (token, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return t, errorf(t.line, t.column, "expected identifier, found %s", t)
+	}
+	return t, nil
+}
+
+func (p *parser) expectInt() (uint, token, error) {
+	t := p.next()
+	if t.kind != tokenNumber {
+		return 0, t, errorf(t.line, t.column, "expected integer, found %s", t)
+	}
+	n, err := strconv.ParseUint(t.text, 10, 32)
+	if err != nil {
+		return 0, t, errorf(t.line, t.column, "invalid integer %s", t)
+	}
+	return uint(n), t, nil
+}
+
+func unsupported(t token, format string, args ...any) *Error {
+	err := errorf(t.line, t.column, format, args...)
+	err.Err = ErrUnsupported
+	return err
+}
+
+func (p *parser) parseProgram() error {
+	if p.is("OPENQASM") {
+		p.next()
+		t := p.next()
+		if t.kind != tokenNumber {
+			return errorf(t.line, t.column, "expected version, found %s", t)
+		}
+		version, err := strconv.ParseFloat(t.text, 64)
+		if err != nil || (version != 2 && version != 3) {
+			return unsupported(t, "OpenQASM version %s", t.text)
+		}
+		if _, err := p.expect(";"); err != nil {
+			return err
+		}
+	}
+
+	for p.peek().kind != tokenEOF {
+		if err := p.parseStatement(); err != nil {
+			return err
+		}
+	}
+
+	if p.qubits == 0 {
+		t := p.peek()
+		return errorf(t.line, t.column, "no qubits declared")
+	}
+
+	return nil
+}
+
+func (p *parser) parseStatement() error {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return errorf(t.line, t.column, "unexpected %s", t)
+	}
+
+	switch t.text {
+	case "OPENQASM":
+		return errorf(t.line, t.column, "OPENQASM must be the first statement")
+	case "include":
+		return p.parseInclude()
+	case "qreg", "creg":
+		return p.parseRegister()
+	case "qubit", "bit":
+		return p.parseDeclaration()
+	case "gate", "opaque":
+		return p.parseGateDef()
+	case "barrier":
+		p.next()
+		if _, err := p.parseArguments(nil); err != nil {
+			return err
+		}
+		_, err := p.expect(";")
+		return err
+	case "measure":
+		return p.parseMeasure()
+	}
+
+	if slices.Contains(unsupportedKeywords, t.text) {
+		return unsupported(t, "%q is not supported", t.text)
+	}
+
+	if p.bits[t.text] {
+		return p.parseMeasureAssignment()
+	}
+
+	return p.parseGateCall()
+}
+
+func (p *parser) parseInclude() error {
+	p.next()
+	t := p.next()
+	if t.kind != tokenString {
+		return errorf(t.line, t.column, "expected file name, found %s", t)
+	}
+	if !slices.Contains(supportedIncludes, t.text) {
+		return unsupported(t, "include %q, only %v are supported", t.text, supportedIncludes)
+	}
+	_, err := p.expect(";")
+	return err
+}
+
+// parseRegister parses the OpenQASM 2 qreg and creg declarations.
+func (p *parser) parseRegister() error {
+	kind := p.next()
+
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.expect("["); err != nil {
+		return err
+	}
+
+	size, _, err := p.expectInt()
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.expect("]"); err != nil {
+		return err
+	}
+
+	if _, err := p.expect(";"); err != nil {
+		return err
+	}
+
+	return p.declare(kind.text == "qreg", name, size)
+}
+
+// parseDeclaration parses the OpenQASM 3 qubit and bit declarations.
+func (p *parser) parseDeclaration() error {
+	kind := p.next()
+
+	size := uint(1)
+	if p.accept("[") {
+		var err error
+		if size, _, err = p.expectInt(); err != nil {
+			return err
+		}
+		if _, err := p.expect("]"); err != nil {
+			return err
+		}
+	}
+
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	if p.is("=") {
+		return unsupported(p.peek(), "initialized declarations are not supported")
+	}
+
+	if _, err := p.expect(";"); err != nil {
+		return err
+	}
+
+	return p.declare(kind.text == "qubit", name, size)
+}
+
+func (p *parser) declare(quantum bool, name token, size uint) error {
+	if _, ok := p.registers[name.text]; ok || p.bits[name.text] {
+		return errorf(name.line, name.column, "%q is already declared", name.text)
+	}
+
+	if !quantum {
+		p.bits[name.text] = true
+		return nil
+	}
+
+	if size == 0 {
+		return errorf(name.line, name.column, "register %q must have at least one qubit", name.text)
+	}
+
+	p.registers[name.text] = register{start: p.qubits, size: size}
+	p.qubits += size
+	return nil
+}
+
+func (p *parser) parseGateDef() error {
+	kind := p.next()
+
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := p.gates[name.text]; ok {
+		return errorf(name.line, name.column, "gate %q is already defined", name.text)
+	}
+
+	def := &gateDef{opaque: kind.text == "opaque"}
+
+	if p.accept("(") {
+		for !p.is(")") {
+			param, err := p.expectIdent()
+			if err != nil {
+				return err
+			}
+			def.params = append(def.params, param.text)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if _, err := p.expect(")"); err != nil {
+			return err
+		}
+	}
+
+	for {
+		qarg, err := p.expectIdent()
+		if err != nil {
+			return err
+		}
+		def.qargs = append(def.qargs, qarg.text)
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if def.opaque {
+		if _, ok := nativeGates[name.text]; !ok {
+			return unsupported(name, "opaque gate %q", name.text)
+		}
+		p.gates[name.text] = def
+		_, err := p.expect(";")
+		return err
+	}
+
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+
+	start := p.pos
+	for depth := 1; ; {
+		t := p.next()
+		switch {
+		case t.kind == tokenEOF:
+			return errorf(t.line, t.column, "unterminated definition of gate %q", name.text)
+		case t.kind == tokenSymbol && t.text == "{":
+			depth++
+		case t.kind == tokenSymbol && t.text == "}":
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+
+	def.body = append(slices.Clone(p.tokens[start:p.pos-1]), token{kind: tokenEOF, line: p.tokens[p.pos-1].line, column: p.tokens[p.pos-1].column})
+
+	// definitions of the built in gates, as written by JobInput.ToQASM, are
+	// accepted but the built in gate is used
+	if _, ok := builtinGates[name.text]; ok {
+		return nil
+	}
+
+	p.gates[name.text] = def
+	return nil
+}
+
+// parseArguments parses a comma separated list of qubit arguments, returning
+// the qubits of each argument.
+func (p *parser) parseArguments(s *scope) ([][]uint, error) {
+	var args [][]uint
+
+	for {
+		name, err := p.expectIdent()
+		if err != nil {
+			return nil, err
+		}
+
+		if s != nil {
+			q, ok := s.qargs[name.text]
+			if !ok {
+				return nil, errorf(name.line, name.column, "unknown qubit argument %q", name.text)
+			}
+			args = append(args, []uint{q})
+		} else {
+			qubits, err := p.resolveQubits(name)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, qubits)
+		}
+
+		if !p.accept(",") {
+			return args, nil
+		}
+	}
+}
+
+func (p *parser) resolveQubits(name token) ([]uint, error) {
+	reg, ok := p.registers[name.text]
+	if !ok {
+		return nil, errorf(name.line, name.column, "unknown quantum register %q", name.text)
+	}
+
+	if !p.accept("[") {
+		qubits := make([]uint, reg.size)
+		for i := range qubits {
+			qubits[i] = reg.start + uint(i)
+		}
+		return qubits, nil
+	}
+
+	index, t, err := p.expectInt()
+	if err != nil {
+		return nil, err
+	}
+
+	if index >= reg.size {
+		return nil, errorf(t.line, t.column, "index %d out of range for register %q of size %d", index, name.text, reg.size)
+	}
+
+	if _, err := p.expect("]"); err != nil {
+		return nil, err
+	}
+
+	return []uint{reg.start + index}, nil
+}
+
+func (p *parser) skipBits() error {
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	if !p.bits[name.text] {
+		return errorf(name.line, name.column, "unknown classical register %q", name.text)
+	}
+
+	if p.accept("[") {
+		if _, _, err := p.expectInt(); err != nil {
+			return err
+		}
+		if _, err := p.expect("]"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) measure() error {
+	p.next()
+
+	args, err := p.parseArguments(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, qubits := range args {
+		for _, q := range qubits {
+			p.measured[q] = true
+		}
+	}
+
+	return nil
+}
+
+// parseMeasure parses "measure q -> c;".
+func (p *parser) parseMeasure() error {
+	if err := p.measure(); err != nil {
+		return err
+	}
+
+	if p.accept("->") {
+		if err := p.skipBits(); err != nil {
+			return err
+		}
+	}
+
+	_, err := p.expect(";")
+	return err
+}
+
+// parseMeasureAssignment parses the OpenQASM 3 "c = measure q;".
+func (p *parser) parseMeasureAssignment() error {
+	if err := p.skipBits(); err != nil {
+		return err
+	}
+
+	if _, err := p.expect("="); err != nil {
+		return err
+	}
+
+	if !p.is("measure") {
+		return unsupported(p.peek(), "classical assignments are not supported")
+	}
+
+	if err := p.measure(); err != nil {
+		return err
+	}
+
+	_, err := p.expect(";")
+	return err
+}
+
+func (p *parser) parseGateCall() error {
+	return p.parseGateCallInScope(nil, 0)
+}
+
+func (p *parser) parseGateCallInScope(s *scope, depth int) error {
+	controls, err := p.parseControlModifiers()
+	if err != nil {
+		return err
+	}
+
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(unsupportedKeywords, name.text) {
+		return unsupported(name, "%q is not supported", name.text)
+	}
+
+	var params []float64
+	if p.accept("(") {
+		for !p.is(")") {
+			value, err := p.parseExpr(s)
+			if err != nil {
+				return err
+			}
+			params = append(params, value)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if _, err := p.expect(")"); err != nil {
+			return err
+		}
+	}
+
+	args, err := p.parseArguments(s)
+	if err != nil {
+		return err
+	}
+
+	if _, err := p.expect(";"); err != nil {
+		return err
+	}
+
+	// broadcast registers over the gate, single qubits are repeated
+	size := 1
+	for _, qubits := range args {
+		if len(qubits) > 1 {
+			if size > 1 && len(qubits) != size {
+				return errorf(name.line, name.column, "registers of different sizes passed to %q", name.text)
+			}
+			size = len(qubits)
+		}
+	}
+
+	for i := range size {
+		qubits := make([]uint, len(args))
+		for j, arg := range args {
+			if len(arg) == 1 {
+				qubits[j] = arg[0]
+			} else {
+				qubits[j] = arg[i]
+			}
+		}
+
+		if err := p.applyGate(name, params, qubits, controls, depth); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseControlModifiers parses the OpenQASM 3 "ctrl @" and "ctrl(n) @"
+// modifiers, returning the number of controls they add.
+func (p *parser) parseControlModifiers() (int, error) {
+	controls := 0
+
+	for p.accept("ctrl") {
+		n := uint(1)
+		if p.accept("(") {
+			var err error
+			var t token
+			if n, t, err = p.expectInt(); err != nil {
+				return 0, err
+			}
+			if n == 0 {
+				return 0, errorf(t.line, t.column, "ctrl modifier requires at least one control")
+			}
+			if _, err := p.expect(")"); err != nil {
+				return 0, err
+			}
+		}
+		if _, err := p.expect("@"); err != nil {
+			return 0, err
+		}
+		controls += int(n)
+	}
+
+	return controls, nil
+}
+
+func (p *parser) applyGate(name token, params []float64, qubits []uint, controls int, depth int) error {
+	for i, q := range qubits {
+		if slices.Contains(qubits[:i], q) {
+			return errorf(name.line, name.column, "qubit %d used more than once by %q", q, name.text)
+		}
+		if p.measured[q] {
+			return unsupported(name, "gate %q on qubit %d after it was measured", name.text, q)
+		}
+	}
+
+	if len(qubits) < controls {
+		return errorf(name.line, name.column, "gate %q expects at least %d qubits, found %d", name.text, controls, len(qubits))
+	}
+	controlQubits, qubits := qubits[:controls], qubits[controls:]
+
+	if def, ok := p.gates[name.text]; ok && !def.opaque {
+		if controls > 0 {
+			return unsupported(name, "ctrl modifier on user defined gate %q", name.text)
+		}
+		return p.expandGate(name, def, params, qubits, depth)
+	}
+
+	if _, ok := builtinGates[name.text]; !ok {
+		return errorf(name.line, name.column, "unknown gate %q", name.text)
+	}
+
+	gates, gateset, err := ConvertGate(name.text, params, qubits)
+	if err != nil {
+		return errorf(name.line, name.column, "%s", err)
+	}
+
+	if p.gateset != "" && p.gateset != gateset {
+		return unsupported(name, "gate %q mixes the %s and %s gate sets", name.text, p.gateset, gateset)
+	}
+	p.gateset = gateset
+
+	if controls > 0 {
+		if gateset == ionq.GatesetNative || len(gates) != 1 {
+			return unsupported(name, "ctrl modifier on gate %q", name.text)
+		}
+		gates[0] = addControls(gates[0], controlQubits)
+	}
+
+	for _, gate := range gates {
+		check := ionq.JobInput{Qubits: p.qubits, Gateset: gateset, Circuit: []ionq.CircuitInput{gate}}
+		if err := check.Validate(); err != nil {
+			e := errorf(name.line, name.column, "%s", err)
+			e.Err = err
+			return e
+		}
+		p.circuit = append(p.circuit, gate)
+	}
+
+	return nil
+}
+
+// addControls adds controls to a converted gate, using the controls field
+// once the gate has more than one control.
+func addControls(gate ionq.CircuitInput, controls []uint) ionq.CircuitInput {
+	all := slices.Clone(controls)
+	if gate.Control != nil {
+		all = append(all, *gate.Control)
+	}
+	all = append(all, gate.Controls...)
+
+	gate.Control = nil
+	gate.Controls = nil
+	if len(all) == 1 {
+		gate.Control = &all[0]
+	} else {
+		gate.Controls = all
+	}
+
+	if gate.Gate == ionq.GateCNOT && len(all) > 1 {
+		gate.Gate = ionq.GateX
+	}
+
+	return gate
+}
+
+func (p *parser) expandGate(name token, def *gateDef, params []float64, qubits []uint, depth int) error {
+	if depth >= maxGateDepth {
+		return errorf(name.line, name.column, "gate %q is nested too deeply", name.text)
+	}
+
+	if len(params) != len(def.params) {
+		return errorf(name.line, name.column, "gate %q expects %d parameters, found %d", name.text, len(def.params), len(params))
+	}
+
+	if len(qubits) != len(def.qargs) {
+		return errorf(name.line, name.column, "gate %q expects %d qubits, found %d", name.text, len(def.qargs), len(qubits))
+	}
+
+	s := &scope{
+		params: make(map[string]float64),
+		qargs:  make(map[string]uint),
+	}
+	for i, param := range def.params {
+		s.params[param] = params[i]
+	}
+	for i, qarg := range def.qargs {
+		s.qargs[qarg] = qubits[i]
+	}
+
+	body := &parser{
+		tokens:    def.body,
+		qubits:    p.qubits,
+		registers: p.registers,
+		bits:      p.bits,
+		gates:     p.gates,
+		measured:  p.measured,
+		gateset:   p.gateset,
+		circuit:   p.circuit,
+	}
+
+	for body.peek().kind != tokenEOF {
+		if body.accept("barrier") {
+			if _, err := body.parseArguments(s); err != nil {
+				return err
+			}
+			if _, err := body.expect(";"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := body.parseGateCallInScope(s, depth+1); err != nil {
+			return err
+		}
+	}
+
+	p.gateset = body.gateset
+	p.circuit = body.circuit
+	return nil
+}
+
+// parseExpr parses a parameter expression, which OpenQASM evaluates to a
+// real number.
+func (p *parser) parseExpr(s *scope) (float64, error) {
+	left, err := p.parseTerm(s)
+	if err != nil {
+		return 0, err
+	}
+
+	for p.is("+") || p.is("-") {
+		op := p.next()
+		right, err := p.parseTerm(s)
+		if err != nil {
+			return 0, err
+		}
+		if op.text == "+" {
+			left += right
+		} else {
+			left -= right
+		}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseTerm(s *scope) (float64, error) {
+	left, err := p.parseUnary(s)
+	if err != nil {
+		return 0, err
+	}
+
+	for p.is("*") || p.is("/") {
+		op := p.next()
+		right, err := p.parseUnary(s)
+		if err != nil {
+			return 0, err
+		}
+		if op.text == "*" {
+			left *= right
+		} else {
+			if right == 0 {
+				return 0, errorf(op.line, op.column, "division by zero")
+			}
+			left /= right
+		}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary(s *scope) (float64, error) {
+	if p.accept("-") {
+		value, err := p.parseUnary(s)
+		return -value, err
+	}
+
+	if p.accept("+") {
+		return p.parseUnary(s)
+	}
+
+	return p.parsePower(s)
+}
+
+func (p *parser) parsePower(s *scope) (float64, error) {
+	base, err := p.parsePrimary(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if p.accept("^") || p.accept("**") {
+		exponent, err := p.parseUnary(s)
+		if err != nil {
+			return 0, err
+		}
+		return math.Pow(base, exponent), nil
+	}
+
+	return base, nil
+}
+
+var functions = map[string]func(float64) float64{
+	"sin":  math.Sin,
+	"cos":  math.Cos,
+	"tan":  math.Tan,
+	"asin": math.Asin,
+	"acos": math.Acos,
+	"atan": math.Atan,
+	"exp":  math.Exp,
+	"ln":   math.Log,
+	"log":  math.Log,
+	"sqrt": math.Sqrt,
+}
+
+var constants = map[string]float64{
+	"pi":    math.Pi,
+	"π":     math.Pi,
+	"tau":   2 * math.Pi,
+	"τ":     2 * math.Pi,
+	"euler": math.E,
+	"ℇ":     math.E,
+}
+
+func (p *parser) parsePrimary(s *scope) (float64, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return 0, errorf(t.line, t.column, "invalid number %s", t)
+		}
+		return value, nil
+	case tokenIdent:
+		if fn, ok := functions[t.text]; ok {
+			if _, err := p.expect("("); err != nil {
+				return 0, err
+			}
+			value, err := p.parseExpr(s)
+			if err != nil {
+				return 0, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return 0, err
+			}
+			return fn(value), nil
+		}
+		if value, ok := constants[t.text]; ok {
+			return value, nil
+		}
+		if s != nil {
+			if value, ok := s.params[t.text]; ok {
+				return value, nil
+			}
+		}
+		return 0, errorf(t.line, t.column, "unknown identifier %q", t.text)
+	case tokenSymbol:
+		if t.text == "(" {
+			value, err := p.parseExpr(s)
+			if err != nil {
+				return 0, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return 0, err
+			}
+			return value, nil
+		}
+	}
+
+	return 0, errorf(t.line, t.column, "unexpected %s in expression", t)
+}
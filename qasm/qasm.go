@@ -0,0 +1,57 @@
+// Package qasm converts OpenQASM 2.0 programs, and the common subset of
+// OpenQASM 3, into an ionq.JobInput.
+//
+// Standard gates are mapped to the QIS gate set, with rotations kept in
+// radians. The gpi, gpi2, ms and zz gates are mapped to the native gate set,
+// with their parameters in turns as expected by the IonQ API; a program
+// cannot mix both gate sets. IonQ measures every qubit at the end of the
+// circuit, so measurements are only accepted once a qubit is no longer used.
+package qasm
+
+import (
+	"errors"
+	"fmt"
+
+	"ionq"
+)
+
+// ErrUnsupported is wrapped by the errors returned for valid OpenQASM
+// constructs that cannot be expressed as an IonQ circuit.
+var ErrUnsupported = errors.New("qasm: unsupported construct")
+
+// Error is returned when a program cannot be parsed or converted. Line and
+// Column are 1-based.
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+	Err     error
+}
+
+func errorf(line, column int, format string, args ...any) *Error {
+	return &Error{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("qasm: %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Parse converts an OpenQASM program into a JobInput using the
+// ionq.circuit.v0 format.
+func Parse(src string) (ionq.JobInput, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return ionq.JobInput{}, err
+	}
+
+	p := newParser(tokens)
+	if err := p.parseProgram(); err != nil {
+		return ionq.JobInput{}, err
+	}
+
+	return p.input(), nil
+}
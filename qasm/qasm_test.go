@@ -0,0 +1,221 @@
+package qasm
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+func mustParse(t *testing.T, src string) ionq.JobInput {
+	t.Helper()
+
+	input, err := Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+func TestParseQASM2(t *testing.T) {
+	input := mustParse(t, `
+OPENQASM 2.0;
+include "qelib1.inc";
+
+// bell state
+qreg q[2];
+creg c[2];
+h q[0];
+cx q[0], q[1];
+barrier q;
+measure q -> c;
+`)
+
+	expected, err := ionq.NewCircuit(2).H(0).CNOT(0, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expected, input); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestParseQASM3(t *testing.T) {
+	input := mustParse(t, `
+OPENQASM 3.0;
+include "stdgates.inc";
+
+qubit[2] q;
+qubit r;
+bit[3] c;
+
+h q;
+ccx q[0], q[1], r;
+rz(-pi / 4) r;
+sdg q[1];
+c[0] = measure q[0];
+c = measure r;
+`)
+
+	expected, err := ionq.NewCircuit(3).
+		H(0).
+		H(1).
+		Toffoli(0, 1, 2).
+		RZ(2, -math.Pi/4).
+		Si(1).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expected, input); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestParseGateDefinition(t *testing.T) {
+	input := mustParse(t, `
+OPENQASM 2.0;
+qreg q[3];
+gate rot(theta, phi) a { rx(theta / 2) a; rz(phi) a; }
+gate entangle(theta) a, b { h a; cx a, b; rot(theta, 2 * pi) b; }
+entangle(pi) q[1], q[2];
+`)
+
+	expected, err := ionq.NewCircuit(3).
+		H(1).
+		CNOT(1, 2).
+		RX(2, math.Pi/2).
+		RZ(2, 2*math.Pi).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expected, input); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestParseNativeGates(t *testing.T) {
+	input := mustParse(t, `
+OPENQASM 2.0;
+opaque gpi(phi) a;
+opaque gpi2(phi) a;
+opaque ms(phi0, phi1, theta) a, b;
+opaque zz(theta) a, b;
+qreg q[2];
+gpi(0.25) q[0];
+gpi2(-0.25) q[1];
+ms(0, 0.5) q[0], q[1];
+ms(0.1, 0.2, 0.125) q[1], q[0];
+zz(0.05) q[0], q[1];
+`)
+
+	expected, err := ionq.NewNativeCircuit(2).
+		GPI(0, 0.25).
+		GPI2(1, 0.75).
+		MS(0, 1, 0, 0.5).
+		PartialMS(1, 0, 0.1, 0.2, 0.125).
+		ZZ(0, 1, 0.05).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expected, input); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestParseExpressions(t *testing.T) {
+	input := mustParse(t, `
+OPENQASM 3;
+qubit q;
+rx(2 * pi ^ 2 / 4 - 1) q;
+ry(cos(0) + sqrt(4) * -1.5e-1) q;
+rz(-(tau - π)) q;
+`)
+
+	expected := []float64{2*math.Pow(math.Pi, 2)/4 - 1, 1 + 2*-0.15, -math.Pi}
+	for i, gate := range input.Circuit {
+		if math.Abs(gate.Rotation-expected[i]) > 1e-12 {
+			t.Fatalf("unexpected rotation for gate %d: %v, expected: %v", i, gate.Rotation, expected[i])
+		}
+	}
+}
+
+func TestParseUSkipsZeroRotations(t *testing.T) {
+	input := mustParse(t, `
+OPENQASM 2.0;
+include "qelib1.inc";
+qreg q[1];
+u3(pi/2, 0, pi) q[0];
+u3(0, 2*pi, 0) q[0];
+u2(0, -2*pi) q[0];
+`)
+
+	expected, err := ionq.NewCircuit(1).RZ(0, math.Pi).RY(0, math.Pi/2).RY(0, math.Pi/2).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(expected, input); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		src         string
+		line        int
+		column      int
+		unsupported bool
+	}{
+		{"reset", "qreg q[1];\nreset q[0];", 2, 1, true},
+		{"classical if", "qreg q[1];\ncreg c[1];\n  if (c == 1) x q[0];", 3, 3, true},
+		{"unknown gate", "qreg q[1];\nfoo q[0];", 2, 1, false},
+		{"gate after measure", "qreg q[1];\nmeasure q[0];\nx q[0];", 3, 1, true},
+		{"index out of range", "qreg q[2];\nx q[2];", 2, 5, false},
+		{"unknown register", "qreg q[2];\nx r[0];", 2, 3, false},
+		{"mixed gate sets", "qreg q[2];\nh q[0];\ngpi(0) q[1];", 3, 1, true},
+		{"include", "OPENQASM 2.0;\ninclude \"other.inc\";", 2, 9, true},
+		{"version", "OPENQASM 4.0;", 1, 10, true},
+		{"missing semicolon", "qreg q[2]\nx q[0];", 2, 1, false},
+		{"parameters", "qreg q[1];\nrx q[0];", 2, 1, false},
+		{"gate modifier", "OPENQASM 3;\nqubit[2] q;\ninv @ x q[0];", 3, 1, true},
+		{"ctrl on native gate", "OPENQASM 3;\nqubit[2] q;\nctrl @ gpi(0) q[0], q[1];", 3, 8, true},
+		{"same qubit", "qreg q[2];\ncx q[1], q[1];", 2, 1, false},
+		{"register size", "qreg q[2];\nqreg r[3];\ncx q, r;", 3, 1, false},
+		{"unterminated comment", "qreg q[2];\n/* comment", 2, 1, false},
+		{"no qubits", "OPENQASM 2.0;\ninclude \"qelib1.inc\";\ncreg c[1];\n", 4, 1, false},
+		{"empty program", "", 1, 1, false},
+	} {
+		_, err := Parse(tc.src)
+
+		var qasmErr *Error
+		if !errors.As(err, &qasmErr) {
+			t.Fatalf("%s: expected *Error, received %v", tc.name, err)
+		}
+
+		if qasmErr.Line != tc.line || qasmErr.Column != tc.column {
+			t.Fatalf("%s: unexpected position %d:%d, expected %d:%d (%s)", tc.name, qasmErr.Line, qasmErr.Column, tc.line, tc.column, err)
+		}
+
+		if errors.Is(err, ErrUnsupported) != tc.unsupported {
+			t.Fatalf("%s: unexpected unsupported error: %s", tc.name, err)
+		}
+	}
+}
+
+func TestParseInvalidNativeAngle(t *testing.T) {
+	_, err := Parse("qreg q[2];\nzz(0.5) q[0], q[1];")
+	if !errors.Is(err, ionq.ErrInvalidCircuit) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
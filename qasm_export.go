@@ -0,0 +1,236 @@
+package ionq
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// QASMVersion selects the OpenQASM version written by ToQASM.
+type QASMVersion int
+
+const (
+	QASM2 QASMVersion = 2
+	QASM3 QASMVersion = 3
+)
+
+// ErrQASMExport is wrapped by the errors returned when a circuit cannot be
+// written as OpenQASM.
+var ErrQASMExport = errors.New("ionq: cannot export to OpenQASM")
+
+// qasmNames maps the QIS gates to their name in qelib1.inc and
+// stdgates.inc.
+var qasmNames = map[string]string{
+	GateX:    "x",
+	GateNot:  "x",
+	GateCNOT: "x",
+	GateY:    "y",
+	GateZ:    "z",
+	GateH:    "h",
+	GateS:    "s",
+	GateSi:   "sdg",
+	GateT:    "t",
+	GateTi:   "tdg",
+	GateV:    "sx",
+	GateVi:   "sxdg",
+	GateRX:   "rx",
+	GateRY:   "ry",
+	GateRZ:   "rz",
+	GateSwap: "swap",
+}
+
+// qasmControlledNames maps a gate and its number of controls to the named
+// controlled gate of qelib1.inc, or of stdgates.inc when qasm3 is set.
+var qasmControlledNames = map[string]map[int]struct {
+	name  string
+	qasm3 bool
+}{
+	"x":    {1: {"cx", true}, 2: {"ccx", true}, 3: {"c3x", false}},
+	"y":    {1: {"cy", true}},
+	"z":    {1: {"cz", true}},
+	"h":    {1: {"ch", true}},
+	"sx":   {1: {"csx", false}},
+	"rx":   {1: {"crx", true}},
+	"ry":   {1: {"cry", true}},
+	"rz":   {1: {"crz", true}},
+	"swap": {1: {"cswap", true}},
+}
+
+// qasmNativeDefinitions defines the native gates, with parameters in turns,
+// in terms of the standard gates so other toolchains can run them.
+var qasmNativeDefinitions = map[string]string{
+	GateGPI:  "gate gpi(phi) a { U(pi, 2*pi*phi, pi - 2*pi*phi) a; }",
+	GateGPI2: "gate gpi2(phi) a { U(pi/2, 2*pi*phi - pi/2, pi/2 - 2*pi*phi) a; }",
+	GateMS: "gate ms(phi0, phi1, theta) a, b { " +
+		"rz(-2*pi*phi0) a; rz(-2*pi*phi1) b; h a; h b; " +
+		"cx a, b; rz(2*pi*theta) b; cx a, b; " +
+		"h a; h b; rz(2*pi*phi0) a; rz(2*pi*phi1) b; }",
+	GateZZ: "gate zz(theta) a, b { cx a, b; rz(2*pi*theta) b; cx a, b; }",
+}
+
+func formatQASMFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func qasmQubits(qubits []uint) string {
+	args := make([]string, len(qubits))
+	for i, q := range qubits {
+		args[i] = fmt.Sprintf("q[%d]", q)
+	}
+	return strings.Join(args, ", ")
+}
+
+func isNativeGate(gate string) bool {
+	switch gate {
+	case GateGPI, GateGPI2, GateMS, GateZZ:
+		return true
+	}
+	return false
+}
+
+// ToQASM returns the gate as an OpenQASM statement acting on the register
+// "q". Native gates are written with their parameters in turns and require
+// the definitions written by JobInput.ToQASM.
+func (g CircuitInput) ToQASM(version QASMVersion) (string, error) {
+	if version != QASM2 && version != QASM3 {
+		return "", fmt.Errorf("%w: unsupported version %d", ErrQASMExport, version)
+	}
+
+	if isNativeGate(g.Gate) {
+		return g.nativeQASM()
+	}
+
+	name, ok := qasmNames[g.Gate]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported gate %q", ErrQASMExport, g.Gate)
+	}
+
+	var controls []uint
+	if g.Control != nil {
+		controls = append(controls, *g.Control)
+	}
+	controls = append(controls, g.Controls...)
+
+	var targets []uint
+	if g.Target != nil {
+		targets = append(targets, *g.Target)
+	}
+	targets = append(targets, g.Targets...)
+
+	var params string
+	switch g.Gate {
+	case GateRX, GateRY, GateRZ:
+		params = fmt.Sprintf("(%s)", formatQASMFloat(g.Rotation))
+	}
+
+	qubits := qasmQubits(append(slices.Clone(controls), targets...))
+
+	if len(controls) == 0 {
+		return fmt.Sprintf("%s%s %s;", name, params, qubits), nil
+	}
+
+	controlled, ok := qasmControlledNames[name][len(controls)]
+	if ok && (version == QASM2 || controlled.qasm3) {
+		return fmt.Sprintf("%s%s %s;", controlled.name, params, qubits), nil
+	}
+
+	if version == QASM2 {
+		return "", fmt.Errorf("%w: %s with %d controls has no OpenQASM 2.0 equivalent", ErrQASMExport, g.Gate, len(controls))
+	}
+
+	modifier := "ctrl"
+	if len(controls) > 1 {
+		modifier = fmt.Sprintf("ctrl(%d)", len(controls))
+	}
+
+	return fmt.Sprintf("%s @ %s%s %s;", modifier, name, params, qubits), nil
+}
+
+func (g CircuitInput) nativeQASM() (string, error) {
+	targets := g.Targets
+	if g.Target != nil {
+		targets = []uint{*g.Target}
+	}
+
+	switch g.Gate {
+	case GateGPI, GateGPI2:
+		if g.Phase == nil || len(targets) != 1 {
+			return "", fmt.Errorf("%w: %s requires a phase and a target", ErrQASMExport, g.Gate)
+		}
+		return fmt.Sprintf("%s(%s) %s;", g.Gate, formatQASMFloat(*g.Phase), qasmQubits(targets)), nil
+	case GateMS:
+		if len(g.Phases) != 2 || len(targets) != 2 {
+			return "", fmt.Errorf("%w: ms requires two phases and two targets", ErrQASMExport)
+		}
+		angle := MaxNativeAngle
+		if g.Angle != nil {
+			angle = *g.Angle
+		}
+		return fmt.Sprintf("ms(%s, %s, %s) %s;", formatQASMFloat(g.Phases[0]), formatQASMFloat(g.Phases[1]), formatQASMFloat(angle), qasmQubits(targets)), nil
+	default:
+		if g.Angle == nil || len(targets) != 2 {
+			return "", fmt.Errorf("%w: zz requires an angle and two targets", ErrQASMExport)
+		}
+		return fmt.Sprintf("zz(%s) %s;", formatQASMFloat(*g.Angle), qasmQubits(targets)), nil
+	}
+}
+
+// ToQASM returns the circuit as an OpenQASM program using a single quantum
+// register "q" and measuring every qubit at the end, as IonQ does. Inputs
+// holding an OpenQASM program return it unchanged.
+func (in JobInput) ToQASM(version QASMVersion) (string, error) {
+	if in.Format == FormatOpenQASM {
+		return in.Data, nil
+	}
+
+	if err := in.Validate(); err != nil {
+		return "", err
+	}
+
+	if len(in.Circuits) > 0 {
+		return "", fmt.Errorf("%w: multicircuit input, export each circuit on its own", ErrQASMExport)
+	}
+
+	var b strings.Builder
+
+	switch version {
+	case QASM2:
+		b.WriteString("OPENQASM 2.0;\ninclude \"qelib1.inc\";\n")
+	case QASM3:
+		b.WriteString("OPENQASM 3.0;\ninclude \"stdgates.inc\";\n")
+	default:
+		return "", fmt.Errorf("%w: unsupported version %d", ErrQASMExport, version)
+	}
+
+	var defined []string
+	for _, gate := range in.Circuit {
+		if definition, ok := qasmNativeDefinitions[gate.Gate]; ok && !slices.Contains(defined, gate.Gate) {
+			defined = append(defined, gate.Gate)
+			fmt.Fprintf(&b, "%s\n", definition)
+		}
+	}
+
+	if version == QASM2 {
+		fmt.Fprintf(&b, "qreg q[%d];\ncreg c[%d];\n", in.Qubits, in.Qubits)
+	} else {
+		fmt.Fprintf(&b, "qubit[%d] q;\nbit[%d] c;\n", in.Qubits, in.Qubits)
+	}
+
+	for i, gate := range in.Circuit {
+		statement, err := gate.ToQASM(version)
+		if err != nil {
+			return "", fmt.Errorf("gate %d: %w", i, err)
+		}
+		fmt.Fprintf(&b, "%s\n", statement)
+	}
+
+	if version == QASM2 {
+		b.WriteString("measure q -> c;\n")
+	} else {
+		b.WriteString("c = measure q;\n")
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,120 @@
+package ionq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJobInputToQASM2(t *testing.T) {
+	input, err := NewCircuit(3).
+		H(0).
+		CNOT(0, 1).
+		RZ(2, 0.5).
+		Toffoli(0, 1, 2).
+		Vi(1).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program, err := input.ToQASM(QASM2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `OPENQASM 2.0;
+include "qelib1.inc";
+qreg q[3];
+creg c[3];
+h q[0];
+cx q[0], q[1];
+rz(0.5) q[2];
+ccx q[0], q[1], q[2];
+sxdg q[1];
+measure q -> c;
+`
+	if program != expected {
+		t.Fatalf("unexpected program:\n%s\nexpected:\n%s", program, expected)
+	}
+}
+
+func TestJobInputToQASM3(t *testing.T) {
+	input, err := NewCircuit(3).
+		Controlled(GateH, []uint{0, 1}, 2).
+		Controlled(GateV, []uint{0}, 1).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program, err := input.ToQASM(QASM3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `OPENQASM 3.0;
+include "stdgates.inc";
+qubit[3] q;
+bit[3] c;
+ctrl(2) @ h q[0], q[1], q[2];
+ctrl @ sx q[0], q[1];
+c = measure q;
+`
+	if program != expected {
+		t.Fatalf("unexpected program:\n%s\nexpected:\n%s", program, expected)
+	}
+
+	if _, err := input.ToQASM(QASM2); !errors.Is(err, ErrQASMExport) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNativeJobInputToQASM(t *testing.T) {
+	input, err := NewNativeCircuit(2).GPI(0, 0.25).MS(0, 1, 0, 0.5).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program, err := input.ToQASM(QASM2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `OPENQASM 2.0;
+include "qelib1.inc";
+` + qasmNativeDefinitions[GateGPI] + `
+` + qasmNativeDefinitions[GateMS] + `
+qreg q[2];
+creg c[2];
+gpi(0.25) q[0];
+ms(0, 0.5, 0.25) q[0], q[1];
+measure q -> c;
+`
+	if program != expected {
+		t.Fatalf("unexpected program:\n%s\nexpected:\n%s", program, expected)
+	}
+}
+
+func TestQASMInputToQASM(t *testing.T) {
+	program := "OPENQASM 2.0;\nqreg q[1];\nh q[0];\n"
+
+	exported, err := NewQASMInput(program).ToQASM(QASM3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exported != program {
+		t.Fatalf("unexpected program: %s", exported)
+	}
+}
+
+func TestMultiCircuitToQASM(t *testing.T) {
+	input := JobInput{
+		Qubits:   1,
+		Circuits: []NamedCircuit{{Name: "h", Circuit: []CircuitInput{{Gate: GateH, Target: qubitRef(0)}}}},
+	}
+
+	if _, err := input.ToQASM(QASM2); !errors.Is(err, ErrQASMExport) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
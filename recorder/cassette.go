@@ -0,0 +1,139 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// redacted replaces the API key in the Authorization headers of recorded
+// requests, keeping the "apiKey" scheme set by the Client.
+const redacted = "apiKey REDACTED"
+
+// Cassette holds the interactions recorded in a file, in the order they were
+// sent.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and the response it received.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Its Authorization header is redacted.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// LoadCassette reads the cassette recorded in the file at path.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, err
+	}
+
+	return &cassette, nil
+}
+
+// Save writes the cassette to the file at path, replacing it atomically.
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// key identifies the requests an interaction can be replayed for: the
+// method, the path with its query parameters sorted and the normalised body.
+type key struct {
+	method string
+	path   string
+	body   string
+}
+
+func requestKey(method, rawURL, body string) key {
+	k := key{method: method, path: rawURL, body: normaliseBody(body)}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return k
+	}
+
+	k.path = u.Path
+	if u.RawQuery != "" {
+		// Encode sorts the parameters by name
+		k.path += "?" + u.Query().Encode()
+	}
+	return k
+}
+
+// normaliseBody returns JSON bodies in compact form with sorted object keys,
+// so requests match however their body was encoded. Numbers are kept as
+// written, so large integers do not lose precision. Other bodies are kept as
+// they are.
+func normaliseBody(body string) string {
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return string(bytes.TrimSpace([]byte(body)))
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		// trailing data, not a single JSON value
+		return string(bytes.TrimSpace([]byte(body)))
+	}
+
+	normalised, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(normalised)
+}
+
+func redact(header http.Header) http.Header {
+	header = header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", redacted)
+	}
+	return header
+}
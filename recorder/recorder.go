@@ -0,0 +1,218 @@
+// Package recorder records the HTTP interactions of a Client into cassette
+// files and replays them, so tests can run against real IonQ traffic without
+// network access or an API key.
+//
+// A Recorder is an http.RoundTripper set on the Client with
+// ionq.WithTransport:
+//
+//	rec, err := recorder.New("testdata/jobs.json", recorder.ModeReplay)
+//	client := ionq.NewClient("", "unused", ionq.WithTransport(rec))
+//
+// In ModeRecord, requests are sent with the underlying transport and the
+// interactions are written to the cassette by Save. API keys are redacted
+// from the recorded Authorization headers. In ModeReplay, no request leaves
+// the process: each request is answered with the first interaction not
+// replayed yet that has the same method, path, query parameters and JSON
+// body, so repeated requests such as job polls replay in order.
+package recorder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrNoInteraction is returned in ModeReplay for requests the cassette holds
+// no interaction for.
+var ErrNoInteraction = errors.New("recorder: no recorded interaction")
+
+// Mode selects whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests and records the interactions.
+	ModeRecord
+)
+
+// Option configures a Recorder created with New.
+type Option func(*Recorder)
+
+// WithTransport sets the transport sending requests in ModeRecord,
+// http.DefaultTransport by default.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// Recorder is an http.RoundTripper recording or replaying interactions.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette *Cassette
+	replayed []bool
+}
+
+// New returns a Recorder for the cassette file at path. In ModeReplay the
+// cassette is loaded and must exist; in ModeRecord it is only written by
+// Save.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		cassette:  &Cassette{},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch mode {
+	case ModeReplay:
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+		r.replayed = make([]bool, len(cassette.Interactions))
+	case ModeRecord:
+	default:
+		return nil, fmt.Errorf("recorder: unknown mode %d", mode)
+	}
+
+	return r, nil
+}
+
+// Save writes the recorded interactions to the cassette file. It does
+// nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cassette.Save(r.path)
+}
+
+// Interactions returns the number of interactions recorded, or held by the
+// cassette in ModeReplay.
+func (r *Recorder) Interactions() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.cassette.Interactions)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+// readBody reads and closes the body of req.
+func readBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (r *Recorder) record(req *http.Request, body string) (*http.Response, error) {
+	// the body of req was consumed, send a copy
+	out := req.Clone(req.Context())
+	if body != "" {
+		out.Body = io.NopCloser(bytes.NewReader([]byte(body)))
+	} else if req.Body != nil {
+		out.Body = http.NoBody
+	}
+
+	res, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: redact(req.Header),
+			Body:   body,
+		},
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     res.Header.Clone(),
+			Body:       string(resBody),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body string) (*http.Response, error) {
+	k := requestKey(req.Method, req.URL.String(), body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] || requestKey(interaction.Request.Method, interaction.Request.URL, interaction.Request.Body) != k {
+			continue
+		}
+		r.replayed[i] = true
+
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, req.Method, k.path)
+}
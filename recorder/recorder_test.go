@@ -0,0 +1,167 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+	"ionq/ionqtest"
+)
+
+func bellRequest(t *testing.T) *ionq.CreateJobRequest {
+	t.Helper()
+
+	input, err := ionq.NewCircuit(2).H(0).CNOT(0, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ionq.CreateJobRequest{Name: "bell", Input: input}
+}
+
+// runJob creates a job, polls it until it completes and returns its output.
+func runJob(ctx context.Context, t *testing.T, client *ionq.Client) ionq.GetJobOutputResponse {
+	t.Helper()
+
+	created, err := client.CreateJob(ctx, bellRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.WaitForJob(ctx, created.Response.ID, &ionq.WaitOptions{Interval: time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := client.GetJobOutput(ctx, &ionq.GetJobOutputRequest{ID: created.Response.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output.Response
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	ts := httptest.NewServer(ionqtest.New(ionqtest.WithDelays(0, 20*time.Millisecond)))
+
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := ionq.NewClient("", ionqtest.DefaultAPIKey, ionq.WithBaseURL(ts.URL), ionq.WithTransport(rec))
+	recorded := runJob(ctx, t, client)
+	ts.Close()
+
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), ionqtest.DefaultAPIKey) || !strings.Contains(string(data), redacted) {
+		t.Fatal("expected the API key to be redacted")
+	}
+
+	rec, err = New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the server is closed, every response comes from the cassette
+	client = ionq.NewClient("", "another-key", ionq.WithBaseURL(ts.URL), ionq.WithTransport(rec))
+	replayed := runJob(ctx, t, client)
+
+	if diff := deep.Equal(recorded, replayed); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	_, err = client.GetJobs(ctx, &ionq.GetJobsRequest{})
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReplayMatchesNormalisedRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	cassette := &Cassette{Interactions: []Interaction{
+		{
+			Request:  Request{Method: "POST", URL: "https://api.ionq.co/v0.3/jobs", Body: `{"name": "a", "shots": 10}`},
+			Response: Response{StatusCode: 200, Body: `{"id": "first"}`},
+		},
+		{
+			Request:  Request{Method: "GET", URL: "https://api.ionq.co/v0.3/jobs?status=completed&limit=2"},
+			Response: Response{StatusCode: 200, Body: `{"jobs": []}`},
+		},
+	}}
+	if err := cassette.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		method, url, body string
+		expected          string
+	}{
+		{"POST", "http://localhost/v0.3/jobs", "{\"shots\":10,\n\"name\":\"a\"}", `{"id": "first"}`},
+		{"GET", "http://localhost/v0.3/jobs?limit=2&status=completed", "", `{"jobs": []}`},
+	} {
+		req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+		res, err := rec.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(body) != tc.expected {
+			t.Fatalf("unexpected body for %s %s: %s", tc.method, tc.url, body)
+		}
+	}
+
+	// every interaction replays once
+	req := httptest.NewRequest("POST", "http://localhost/v0.3/jobs", strings.NewReader(`{"name":"a","shots":10}`))
+	if _, err := rec.RoundTrip(req); !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReplayRequiresCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNormaliseBodyKeepsLargeIntegers(t *testing.T) {
+	recorded := normaliseBody(`{"id": 9007199254740993, "shots": 10}`)
+
+	if normaliseBody(`{"shots":10,"id":9007199254740993}`) != recorded {
+		t.Fatalf("expected equal bodies to match: %s", recorded)
+	}
+
+	if normaliseBody(`{"id": 9007199254740992, "shots": 10}`) == recorded {
+		t.Fatalf("expected integers above 2^53 to stay distinct: %s", recorded)
+	}
+}
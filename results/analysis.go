@@ -0,0 +1,214 @@
+package results
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+
+	"ionq"
+)
+
+// Marginal returns the distribution of the given qubits, where qubit i of
+// the marginal is qubits[i] of d.
+func (d *Distribution) Marginal(qubits ...uint) (*Distribution, error) {
+	if len(qubits) == 0 {
+		return nil, fmt.Errorf("%w: marginal requires at least one qubit", ErrInvalidResults)
+	}
+
+	seen := make(map[uint]bool, len(qubits))
+	for _, q := range qubits {
+		if q >= d.qubits {
+			return nil, fmt.Errorf("%w: qubit %d out of range for %d qubits", ErrInvalidResults, q, d.qubits)
+		}
+		if seen[q] {
+			return nil, fmt.Errorf("%w: qubit %d repeated in marginal", ErrInvalidResults, q)
+		}
+		seen[q] = true
+	}
+
+	marginal := &Distribution{qubits: uint(len(qubits)), probs: make(map[uint64]float64)}
+	for index, prob := range d.probs {
+		var reduced uint64
+		for i, q := range qubits {
+			if index&(1<<q) != 0 {
+				reduced |= 1 << i
+			}
+		}
+		marginal.probs[reduced] += prob
+	}
+
+	return marginal, nil
+}
+
+// ExpectationZ returns the expectation value of the product of Pauli Z
+// operators acting on the given qubits, between -1 and 1. Without qubits it
+// returns the total probability.
+func (d *Distribution) ExpectationZ(qubits ...uint) (float64, error) {
+	var mask uint64
+	for _, q := range qubits {
+		if q >= d.qubits {
+			return 0, fmt.Errorf("%w: qubit %d out of range for %d qubits", ErrInvalidResults, q, d.qubits)
+		}
+		mask |= 1 << q
+	}
+
+	var expectation float64
+	for index, prob := range d.probs {
+		if bits.OnesCount64(index&mask)%2 == 0 {
+			expectation += prob
+		} else {
+			expectation -= prob
+		}
+	}
+
+	return expectation, nil
+}
+
+// Expectation returns the expectation value of a Pauli string made of I and
+// Z operators, with one operator per qubit written in order. For example
+// "ZIZ" with Qubit0Right measures qubits 0 and 2.
+func (d *Distribution) Expectation(pauli string, order Order) (float64, error) {
+	if uint(len(pauli)) != d.qubits {
+		return 0, fmt.Errorf("%w: pauli string %q must have %d operators", ErrInvalidResults, pauli, d.qubits)
+	}
+
+	var qubits []uint
+	for i := range d.qubits {
+		q := i
+		if order == Qubit0Right {
+			q = d.qubits - 1 - i
+		}
+
+		switch pauli[i] {
+		case 'I':
+		case 'Z':
+			qubits = append(qubits, q)
+		default:
+			return 0, fmt.Errorf("%w: pauli string %q must only hold I and Z", ErrInvalidResults, pauli)
+		}
+	}
+
+	return d.ExpectationZ(qubits...)
+}
+
+func checkComparable(a, b *Distribution) error {
+	if a.qubits != b.qubits {
+		return fmt.Errorf("%w: cannot compare distributions of %d and %d qubits", ErrInvalidResults, a.qubits, b.qubits)
+	}
+	return nil
+}
+
+// TotalVariationDistance returns half the sum of the absolute differences of
+// the probabilities of a and b, between 0 for equal distributions and 1 for
+// disjoint ones.
+func TotalVariationDistance(a, b *Distribution) (float64, error) {
+	if err := checkComparable(a, b); err != nil {
+		return 0, err
+	}
+
+	var distance float64
+	for index, p := range a.probs {
+		distance += math.Abs(p - b.probs[index])
+	}
+	for index, q := range b.probs {
+		if _, ok := a.probs[index]; !ok {
+			distance += q
+		}
+	}
+
+	return distance / 2, nil
+}
+
+// HellingerDistance returns the Hellinger distance of a and b,
+// sqrt(1 - sum(sqrt(p*q))), between 0 for equal distributions and 1 for
+// disjoint ones.
+func HellingerDistance(a, b *Distribution) (float64, error) {
+	if err := checkComparable(a, b); err != nil {
+		return 0, err
+	}
+
+	var coefficient float64
+	for index, p := range a.probs {
+		coefficient += math.Sqrt(p * b.probs[index])
+	}
+
+	// rounding can bring the coefficient of equal distributions above 1
+	return math.Sqrt(max(0, 1-coefficient)), nil
+}
+
+// Outcome is a basis state and its probability.
+type Outcome struct {
+	Index       uint64
+	Bitstring   string
+	Probability float64
+}
+
+// TopK returns the k most likely basis states, most likely first, with their
+// bitstrings written in order. Ties are ordered by basis state index.
+func (d *Distribution) TopK(k int, order Order) []Outcome {
+	indices := d.indices()
+	slices.SortStableFunc(indices, func(a, b uint64) int {
+		switch pa, pb := d.probs[a], d.probs[b]; {
+		case pa > pb:
+			return -1
+		case pa < pb:
+			return 1
+		}
+		return 0
+	})
+
+	indices = indices[:min(max(k, 0), len(indices))]
+
+	outcomes := make([]Outcome, len(indices))
+	for i, index := range indices {
+		outcomes[i] = Outcome{Index: index, Bitstring: d.Bitstring(index, order), Probability: d.probs[index]}
+	}
+	return outcomes
+}
+
+// Comparison holds the averaged and sharpened distributions of a job with
+// the distances between them.
+type Comparison struct {
+	Averaged  *Distribution
+	Sharpened *Distribution
+
+	TotalVariation float64
+	Hellinger      float64
+}
+
+// Compare returns the Comparison of the outputs returned by
+// Client.CompareJobOutputs for a circuit of the given number of qubits. A
+// zero qubits uses the fewest qubits holding every basis state of both
+// outputs.
+func Compare(outputs *ionq.JobOutputComparison, qubits uint) (*Comparison, error) {
+	if qubits == 0 {
+		for _, output := range []ionq.GetJobOutputResponse{outputs.Averaged, outputs.Sharpened} {
+			d, err := FromOutput(output, 0)
+			if err != nil {
+				return nil, err
+			}
+			qubits = max(qubits, d.qubits)
+		}
+	}
+
+	averaged, err := FromOutput(outputs.Averaged, qubits)
+	if err != nil {
+		return nil, err
+	}
+
+	sharpened, err := FromOutput(outputs.Sharpened, qubits)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &Comparison{Averaged: averaged, Sharpened: sharpened}
+	if comparison.TotalVariation, err = TotalVariationDistance(averaged, sharpened); err != nil {
+		return nil, err
+	}
+	if comparison.Hellinger, err = HellingerDistance(averaged, sharpened); err != nil {
+		return nil, err
+	}
+
+	return comparison, nil
+}
@@ -0,0 +1,164 @@
+package results
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+const epsilon = 1e-9
+
+func TestMarginal(t *testing.T) {
+	d := mustFromOutput(t, ionq.GetJobOutputResponse{"1": 0.5, "6": 0.25, "7": 0.25}, 3)
+
+	marginal, err := d.Marginal(2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// qubit 0 of the marginal is qubit 2, qubit 1 is qubit 0
+	expected := map[string]float64{"10": 0.5, "01": 0.25, "11": 0.25}
+	if diff := deep.Equal(expected, marginal.Probabilities(Qubit0Right)); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	for _, qubits := range [][]uint{{}, {3}, {1, 1}} {
+		if _, err := d.Marginal(qubits...); !errors.Is(err, ErrInvalidResults) {
+			t.Fatalf("unexpected error for %v: %v", qubits, err)
+		}
+	}
+}
+
+func TestExpectation(t *testing.T) {
+	// bell state with an extra qubit always 1
+	d := mustFromOutput(t, ionq.GetJobOutputResponse{"4": 0.5, "7": 0.5}, 3)
+
+	for _, tc := range []struct {
+		qubits   []uint
+		expected float64
+	}{
+		{[]uint{0}, 0},
+		{[]uint{0, 1}, 1},
+		{[]uint{2}, -1},
+		{[]uint{0, 1, 2}, -1},
+		{nil, 1},
+	} {
+		expectation, err := d.ExpectationZ(tc.qubits...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(expectation-tc.expected) > epsilon {
+			t.Fatalf("unexpected expectation for %v: %f, expected: %f", tc.qubits, expectation, tc.expected)
+		}
+	}
+
+	expectation, err := d.Expectation("ZII", Qubit0Right)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expectation != -1 {
+		t.Fatalf("unexpected expectation: %f", expectation)
+	}
+
+	expectation, err = d.Expectation("ZZI", Qubit0Left)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expectation != 1 {
+		t.Fatalf("unexpected expectation: %f", expectation)
+	}
+
+	for _, pauli := range []string{"ZZ", "XII"} {
+		if _, err := d.Expectation(pauli, Qubit0Right); !errors.Is(err, ErrInvalidResults) {
+			t.Fatalf("unexpected error for %s: %v", pauli, err)
+		}
+	}
+}
+
+func TestDistances(t *testing.T) {
+	a := mustFromOutput(t, ionq.GetJobOutputResponse{"0": 0.5, "3": 0.5}, 2)
+	b := mustFromOutput(t, ionq.GetJobOutputResponse{"0": 0.5, "1": 0.5}, 2)
+	c := mustFromOutput(t, ionq.GetJobOutputResponse{"1": 0.5, "2": 0.5}, 2)
+
+	for _, tc := range []struct {
+		name        string
+		x, y        *Distribution
+		tvd, hellin float64
+	}{
+		{"equal", a, a, 0, 0},
+		{"overlapping", a, b, 0.5, math.Sqrt(0.5)},
+		{"disjoint", a, c, 1, 1},
+	} {
+		tvd, err := TotalVariationDistance(tc.x, tc.y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(tvd-tc.tvd) > epsilon {
+			t.Fatalf("%s: unexpected total variation distance: %f", tc.name, tvd)
+		}
+
+		hellinger, err := HellingerDistance(tc.x, tc.y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(hellinger-tc.hellin) > 1e-6 {
+			t.Fatalf("%s: unexpected hellinger distance: %f", tc.name, hellinger)
+		}
+	}
+
+	if _, err := TotalVariationDistance(a, mustFromOutput(t, ionq.GetJobOutputResponse{"0": 1}, 1)); !errors.Is(err, ErrInvalidResults) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	d := mustFromOutput(t, ionq.GetJobOutputResponse{"0": 0.25, "1": 0.5, "2": 0.125, "3": 0.125}, 2)
+
+	expected := []Outcome{
+		{Index: 1, Bitstring: "01", Probability: 0.5},
+		{Index: 0, Bitstring: "00", Probability: 0.25},
+		{Index: 2, Bitstring: "10", Probability: 0.125},
+	}
+
+	if diff := deep.Equal(expected, d.TopK(3, Qubit0Right)); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if len(d.TopK(10, Qubit0Right)) != 4 || len(d.TopK(-1, Qubit0Right)) != 0 {
+		t.Fatal("unexpected number of outcomes")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	comparison, err := Compare(&ionq.JobOutputComparison{
+		Averaged:  ionq.GetJobOutputResponse{"0": 0.5, "1": 0.25, "2": 0.25},
+		Sharpened: ionq.GetJobOutputResponse{"0": 1},
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if comparison.Averaged.Qubits() != 2 || comparison.Sharpened.Qubits() != 2 {
+		t.Fatalf("unexpected qubits: %d and %d", comparison.Averaged.Qubits(), comparison.Sharpened.Qubits())
+	}
+
+	if math.Abs(comparison.TotalVariation-0.5) > epsilon {
+		t.Fatalf("unexpected total variation distance: %v", comparison.TotalVariation)
+	}
+
+	if expected := math.Sqrt(1 - math.Sqrt(0.5)); math.Abs(comparison.Hellinger-expected) > epsilon {
+		t.Fatalf("unexpected Hellinger distance: %v, expected %v", comparison.Hellinger, expected)
+	}
+
+	_, err = Compare(&ionq.JobOutputComparison{
+		Averaged:  ionq.GetJobOutputResponse{"4": 1},
+		Sharpened: ionq.GetJobOutputResponse{"0": 1},
+	}, 2)
+	if !errors.Is(err, ErrInvalidResults) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
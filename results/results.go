@@ -0,0 +1,263 @@
+// Package results analyses the output of IonQ jobs.
+//
+// The API returns results as probabilities keyed by the decimal index of
+// each basis state, with qubit 0 as the least significant bit. A
+// Distribution holds these probabilities with the number of qubits of the
+// circuit, and converts them to bitstrings in either qubit order.
+package results
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ionq"
+)
+
+// MaxQubits is the largest number of qubits a Distribution can hold.
+const MaxQubits = 64
+
+// ErrInvalidResults is wrapped by the errors returned for results that do
+// not match their circuit.
+var ErrInvalidResults = errors.New("results: invalid results")
+
+// Order is the order of the qubits in a bitstring.
+type Order int
+
+const (
+	// Qubit0Right writes qubit 0 as the rightmost bit, so a bitstring is the
+	// binary form of the basis state index. Qiskit uses this order.
+	Qubit0Right Order = iota
+
+	// Qubit0Left writes qubit 0 as the leftmost bit.
+	Qubit0Left
+)
+
+// Distribution is the probability of each basis state of a circuit. Basis
+// states missing from the distribution have a zero probability.
+type Distribution struct {
+	qubits uint
+	probs  map[uint64]float64
+}
+
+// FromOutput returns the Distribution of a job output for a circuit of the
+// given number of qubits, usually Job.Qubits. A zero qubits uses the fewest
+// qubits holding every basis state of the output.
+func FromOutput(output ionq.GetJobOutputResponse, qubits uint) (*Distribution, error) {
+	return FromOutputStream(func(yield func(ionq.OutputEntry, error) bool) {
+		for state, prob := range output {
+			if !yield(ionq.OutputEntry{State: state, Probability: prob}, nil) {
+				return
+			}
+		}
+	}, qubits)
+}
+
+// FromOutputStream is FromOutput for an output decoded as it is read, as
+// returned by Client.StreamJobOutput or ionq.DecodeJobOutput.
+func FromOutputStream(entries iter.Seq2[ionq.OutputEntry, error], qubits uint) (*Distribution, error) {
+	if qubits > MaxQubits {
+		return nil, fmt.Errorf("%w: %d qubits, the maximum is %d", ErrInvalidResults, qubits, MaxQubits)
+	}
+
+	probs := make(map[uint64]float64)
+	var highest uint64
+	for entry, err := range entries {
+		if err != nil {
+			return nil, err
+		}
+
+		index, err := strconv.ParseUint(entry.State, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: basis state %q is not a decimal index", ErrInvalidResults, entry.State)
+		}
+
+		if entry.Probability < 0 {
+			return nil, fmt.Errorf("%w: negative probability for basis state %s", ErrInvalidResults, entry.State)
+		}
+
+		probs[index] = float64(entry.Probability)
+		highest = max(highest, index)
+	}
+
+	if qubits == 0 {
+		qubits = uint(max(bits.Len64(highest), 1))
+	} else if qubits < MaxQubits && highest >= 1<<qubits {
+		return nil, fmt.Errorf("%w: basis state %d out of range for %d qubits", ErrInvalidResults, highest, qubits)
+	}
+
+	return &Distribution{qubits: qubits, probs: probs}, nil
+}
+
+// FromProbabilities returns the Distribution of the given probabilities,
+// keyed by bitstrings of equal length written in order.
+func FromProbabilities(probs map[string]float64, order Order) (*Distribution, error) {
+	d := &Distribution{probs: make(map[uint64]float64, len(probs))}
+
+	first := true
+	for bitstring, prob := range probs {
+		if first {
+			d.qubits = uint(len(bitstring))
+			first = false
+		}
+
+		index, err := parseBitstring(bitstring, d.qubits, order)
+		if err != nil {
+			return nil, err
+		}
+
+		if prob < 0 {
+			return nil, fmt.Errorf("%w: negative probability for basis state %s", ErrInvalidResults, bitstring)
+		}
+
+		d.probs[index] += prob
+	}
+
+	return d, nil
+}
+
+func parseBitstring(bitstring string, qubits uint, order Order) (uint64, error) {
+	if uint(len(bitstring)) != qubits || qubits == 0 || qubits > MaxQubits {
+		return 0, fmt.Errorf("%w: bitstring %q must have %d bits", ErrInvalidResults, bitstring, qubits)
+	}
+
+	var index uint64
+	for i := range qubits {
+		// the bit of qubit i
+		c := bitstring[i]
+		if order == Qubit0Right {
+			c = bitstring[qubits-1-i]
+		}
+
+		switch c {
+		case '0':
+		case '1':
+			index |= 1 << i
+		default:
+			return 0, fmt.Errorf("%w: bitstring %q must only hold 0 and 1", ErrInvalidResults, bitstring)
+		}
+	}
+
+	return index, nil
+}
+
+// Qubits returns the number of qubits of the distribution.
+func (d *Distribution) Qubits() uint {
+	return d.qubits
+}
+
+// Probability returns the probability of the basis state with the given
+// index, where qubit 0 is the least significant bit.
+func (d *Distribution) Probability(index uint64) float64 {
+	return d.probs[index]
+}
+
+// Total returns the sum of the probabilities, 1 up to rounding for job
+// outputs.
+func (d *Distribution) Total() float64 {
+	var total float64
+	for _, prob := range d.probs {
+		total += prob
+	}
+	return total
+}
+
+// Bitstring returns the bitstring of the basis state with the given index.
+func (d *Distribution) Bitstring(index uint64, order Order) string {
+	var b strings.Builder
+	b.Grow(int(d.qubits))
+	for i := range d.qubits {
+		q := i
+		if order == Qubit0Right {
+			q = d.qubits - 1 - i
+		}
+
+		if index&(1<<q) != 0 {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+// Probabilities returns the probabilities keyed by bitstrings written in
+// order.
+func (d *Distribution) Probabilities(order Order) map[string]float64 {
+	probs := make(map[string]float64, len(d.probs))
+	for index, prob := range d.probs {
+		probs[d.Bitstring(index, order)] = prob
+	}
+	return probs
+}
+
+// indices returns the basis states of the distribution in increasing order.
+func (d *Distribution) indices() []uint64 {
+	indices := make([]uint64, 0, len(d.probs))
+	for index := range d.probs {
+		indices = append(indices, index)
+	}
+	slices.Sort(indices)
+	return indices
+}
+
+// Counts converts the probabilities to counts over the given number of
+// shots, keyed by bitstrings written in order. Counts are rounded so they
+// sum to shots, giving the remaining shots to the largest remainders.
+func (d *Distribution) Counts(shots uint, order Order) map[string]int {
+	counts := make(map[string]int, len(d.probs))
+	for index, count := range d.countsByIndex(shots) {
+		counts[d.Bitstring(index, order)] = count
+	}
+	return counts
+}
+
+// countsByIndex is Counts keyed by basis state index, leaving out zero
+// counts.
+func (d *Distribution) countsByIndex(shots uint) map[uint64]int {
+	total := d.Total()
+	counts := make(map[uint64]int, len(d.probs))
+	if total == 0 {
+		return counts
+	}
+
+	type remainder struct {
+		index uint64
+		value float64
+	}
+
+	indices := d.indices()
+	remainders := make([]remainder, 0, len(indices))
+	assigned := 0
+	for _, index := range indices {
+		exact := d.probs[index] / total * float64(shots)
+		count := int(exact)
+		assigned += count
+		remainders = append(remainders, remainder{index, exact - float64(count)})
+
+		if count > 0 {
+			counts[index] = count
+		}
+	}
+
+	slices.SortStableFunc(remainders, func(a, b remainder) int {
+		switch {
+		case a.value > b.value:
+			return -1
+		case a.value < b.value:
+			return 1
+		}
+		return 0
+	})
+
+	for i := 0; assigned < int(shots) && i < len(remainders); i++ {
+		counts[remainders[i].index]++
+		assigned++
+	}
+
+	return counts
+}
@@ -0,0 +1,100 @@
+package results
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+func mustFromOutput(t *testing.T, output ionq.GetJobOutputResponse, qubits uint) *Distribution {
+	t.Helper()
+
+	d, err := FromOutput(output, qubits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestProbabilitiesOrder(t *testing.T) {
+	// qubit 0 set with probability 0.75, qubits 0 and 2 set with 0.25
+	d := mustFromOutput(t, ionq.GetJobOutputResponse{"1": 0.75, "5": 0.25}, 3)
+
+	if diff := deep.Equal(map[string]float64{"001": 0.75, "101": 0.25}, d.Probabilities(Qubit0Right)); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if diff := deep.Equal(map[string]float64{"100": 0.75, "101": 0.25}, d.Probabilities(Qubit0Left)); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestFromOutputInfersQubits(t *testing.T) {
+	if qubits := mustFromOutput(t, ionq.GetJobOutputResponse{"0": 0.5, "6": 0.5}, 0).Qubits(); qubits != 3 {
+		t.Fatalf("unexpected qubits: %d", qubits)
+	}
+
+	if qubits := mustFromOutput(t, ionq.GetJobOutputResponse{"0": 1}, 0).Qubits(); qubits != 1 {
+		t.Fatalf("unexpected qubits: %d", qubits)
+	}
+}
+
+func TestFromOutputErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		output ionq.GetJobOutputResponse
+		qubits uint
+	}{
+		{"not decimal", ionq.GetJobOutputResponse{"01": 1, "x": 0}, 2},
+		{"out of range", ionq.GetJobOutputResponse{"4": 1}, 2},
+		{"negative", ionq.GetJobOutputResponse{"0": -1}, 1},
+		{"too many qubits", ionq.GetJobOutputResponse{"0": 1}, 65},
+	} {
+		if _, err := FromOutput(tc.output, tc.qubits); !errors.Is(err, ErrInvalidResults) {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestFromProbabilities(t *testing.T) {
+	d, err := FromProbabilities(map[string]float64{"01": 0.5, "10": 0.5}, Qubit0Left)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Qubits() != 2 || d.Probability(1) != 0.5 || d.Probability(2) != 0.5 {
+		t.Fatalf("unexpected distribution: %+v", d)
+	}
+
+	for _, probs := range []map[string]float64{
+		{"01": 0.5, "1": 0.5},
+		{"0a": 1},
+		{"": 1},
+	} {
+		if _, err := FromProbabilities(probs, Qubit0Right); !errors.Is(err, ErrInvalidResults) {
+			t.Fatalf("unexpected error for %v: %v", probs, err)
+		}
+	}
+}
+
+func TestCounts(t *testing.T) {
+	d := mustFromOutput(t, ionq.GetJobOutputResponse{"0": 0.333, "1": 0.333, "2": 0.334}, 2)
+
+	counts := d.Counts(100, Qubit0Right)
+
+	// 33.3, 33.3 and 33.4 leave one shot to the largest remainder
+	if diff := deep.Equal(map[string]int{"00": 33, "01": 33, "10": 34}, counts); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	total := 0
+	for _, count := range d.Counts(7, Qubit0Right) {
+		total += count
+	}
+	if total != 7 {
+		t.Fatalf("expected counts to sum to the shots, received %d", total)
+	}
+}
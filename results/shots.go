@@ -0,0 +1,94 @@
+package results
+
+import (
+	"iter"
+	"math/rand/v2"
+)
+
+// SynthesizeShots yields made-up outcomes for the given number of shots as
+// bitstrings written in order, for tooling expecting the per-shot memory of
+// Qiskit.
+//
+// The API only returns aggregated probabilities, so these are not the shots
+// of the job: their histogram is exactly Counts(shots, order), in an order
+// drawn from seed. The same seed always yields the same shots. Correlations
+// between consecutive shots of the real execution, such as drift, are not
+// recoverable from the results.
+//
+// Shots are drawn one at a time, so a large number of shots is never held as
+// a whole. A distribution with a zero total yields no shots.
+func (d *Distribution) SynthesizeShots(shots uint, order Order, seed uint64) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for index := range d.SynthesizeShotIndices(shots, seed) {
+			if !yield(d.Bitstring(index, order)) {
+				return
+			}
+		}
+	}
+}
+
+// SynthesizeShotIndices is SynthesizeShots yielding basis state indices,
+// where qubit 0 is the least significant bit, instead of bitstrings.
+func (d *Distribution) SynthesizeShotIndices(shots uint, seed uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		counts := d.countsByIndex(shots)
+
+		indices := make([]uint64, 0, len(counts))
+		for _, index := range d.indices() {
+			if counts[index] > 0 {
+				indices = append(indices, index)
+			}
+		}
+
+		remaining := newFenwick(len(indices))
+		left := 0
+		for i, index := range indices {
+			remaining.add(i, counts[index])
+			left += counts[index]
+		}
+
+		rng := rand.New(rand.NewPCG(seed, seed))
+		for ; left > 0; left-- {
+			// draw without replacement, so the shots realize the counts
+			i := remaining.search(rng.IntN(left))
+			remaining.add(i, -1)
+
+			if !yield(indices[i]) {
+				return
+			}
+		}
+	}
+}
+
+// fenwick is a binary indexed tree over the remaining shots of each basis
+// state, so a shot is drawn in logarithmic time.
+type fenwick []int
+
+func newFenwick(n int) fenwick {
+	return make(fenwick, n+1)
+}
+
+func (f fenwick) add(i, delta int) {
+	for i++; i < len(f); i += i & -i {
+		f[i] += delta
+	}
+}
+
+// search returns the position holding the shot of the given rank, counting
+// from zero.
+func (f fenwick) search(rank int) int {
+	pos := 0
+	step := 1
+	for step*2 < len(f) {
+		step *= 2
+	}
+
+	for ; step > 0; step /= 2 {
+		if next := pos + step; next < len(f) && f[next] <= rank {
+			pos = next
+			rank -= f[next]
+		}
+	}
+
+	return pos
+}
@@ -0,0 +1,91 @@
+package results
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"ionq"
+)
+
+func TestSynthesizeShotsMatchesCounts(t *testing.T) {
+	d := mustFromOutput(t, ionq.GetJobOutputResponse{"0": 0.5, "1": 0.3, "6": 0.2}, 3)
+
+	histogram := make(map[string]int)
+	shots := 0
+	for bitstring := range d.SynthesizeShots(1000, Qubit0Right, 42) {
+		histogram[bitstring]++
+		shots++
+	}
+
+	if shots != 1000 {
+		t.Fatalf("unexpected shots: %d", shots)
+	}
+
+	if diff := deep.Equal(d.Counts(1000, Qubit0Right), histogram); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestSynthesizeShotsSeeded(t *testing.T) {
+	d := mustFromOutput(t, ionq.GetJobOutputResponse{"0": 0.5, "3": 0.5}, 2)
+
+	first := slices.Collect(d.SynthesizeShots(100, Qubit0Left, 7))
+	if diff := deep.Equal(first, slices.Collect(d.SynthesizeShots(100, Qubit0Left, 7))); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if slices.Equal(first, slices.Collect(d.SynthesizeShots(100, Qubit0Left, 8))) {
+		t.Fatal("expected different seeds to shuffle the shots differently")
+	}
+
+	// the shots are shuffled rather than grouped by outcome
+	if strings.Join(first, "") == strings.Repeat("00", 50)+strings.Repeat("11", 50) {
+		t.Fatal("expected shuffled shots")
+	}
+}
+
+func TestSynthesizeShotIndicesStopsEarly(t *testing.T) {
+	d := mustFromOutput(t, ionq.GetJobOutputResponse{"2": 1}, 2)
+
+	var indices []uint64
+	for index := range d.SynthesizeShotIndices(1_000_000, 1) {
+		indices = append(indices, index)
+		if len(indices) == 3 {
+			break
+		}
+	}
+
+	if diff := deep.Equal([]uint64{2, 2, 2}, indices); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+}
+
+func TestSynthesizeShotsEmpty(t *testing.T) {
+	d := mustFromOutput(t, ionq.GetJobOutputResponse{"0": 0}, 1)
+
+	if shots := slices.Collect(d.SynthesizeShots(10, Qubit0Right, 1)); len(shots) != 0 {
+		t.Fatalf("unexpected shots: %v", shots)
+	}
+}
+
+func TestFromOutputStream(t *testing.T) {
+	output := ionq.GetJobOutputResponse{"0": 0.25, "5": 0.75}
+	body := `{"0": 0.25, "5": 0.75}`
+
+	d, err := FromOutputStream(ionq.DecodeJobOutput(strings.NewReader(body)), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := deep.Equal(mustFromOutput(t, output, 3).Probabilities(Qubit0Right), d.Probabilities(Qubit0Right)); len(diff) > 0 {
+		t.Fatalf("unexpected diff: %s", diff)
+	}
+
+	if _, err := FromOutputStream(ionq.DecodeJobOutput(strings.NewReader(`{"0": "x"}`)), 1); err == nil {
+		t.Fatal("expected a decoding error")
+	}
+
+}
@@ -0,0 +1,132 @@
+package ionq
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the Client retries requests that fail with a
+// transient error. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	// request. Values lower than 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry. It doubles for each
+	// following retry up to MaxBackoff, or without limit when MaxBackoff is 0,
+	// with jitter applied.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// RetryNonIdempotent allows POST requests to be retried on network errors
+	// and 5xx responses. This is unsafe for CreateJob because the job may have
+	// been created even though the response was lost, so it is disabled by
+	// default. POST requests are always retried on 429 since the request was
+	// rejected before being processed.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy suitable for most callers.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// WithRetryPolicy sets the RetryPolicy used for every request sent by the
+// Client.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(cfg *clientConfig) {
+		cfg.retry = policy
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether a request that ended with the given status and
+// error can be sent again.
+func (p RetryPolicy) shouldRetry(req *http.Request, status int, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body cannot be rewound
+		return false
+	}
+
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+
+	safe := isIdempotent(req.Method) || p.RetryNonIdempotent
+
+	if status == 0 {
+		// the request failed before receiving a response
+		return safe
+	}
+
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return safe
+	}
+
+	return false
+}
+
+// backoff returns how long to wait before the given retry, honouring the
+// Retry-After header when the server sent one.
+func (p RetryPolicy) backoff(retry int, header http.Header) time.Duration {
+	if wait, ok := parseRetryAfter(header); ok {
+		return wait
+	}
+
+	wait := p.InitialBackoff
+	for i := 1; i < retry && (p.MaxBackoff == 0 || wait < p.MaxBackoff) && wait <= math.MaxInt64/2; i++ {
+		wait *= 2
+	}
+
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+
+	if wait <= 0 {
+		return 0
+	}
+
+	// equal jitter: wait between half and the full backoff
+	half := wait / 2
+	return half + rand.N(wait-half+1)
+}
+
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,222 @@
+package ionq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestRetryGetJobOnServiceUnavailable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(503)
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(502)
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(200).
+		JSON(&GetJobResponse{ID: "some-id"})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithRetryPolicy(testRetryPolicy()))
+	jobResponseWithStatus, err := client.GetJob(ctx, &GetJobRequest{ID: "some-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if jobResponseWithStatus.Response.ID != "some-id" {
+		t.Fatalf("unexpected id: %s", jobResponseWithStatus.Response.ID)
+	}
+
+	if !gock.IsDone() {
+		t.Fatal("expected all mocks to be used")
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(jobsPath).
+		Times(3).
+		Reply(503)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithRetryPolicy(testRetryPolicy()))
+	_, err := client.GetJobs(ctx, &GetJobsRequest{})
+	assertAPIError(t, err, http.StatusServiceUnavailable, ErrServer)
+
+	if !gock.IsDone() {
+		t.Fatal("expected 3 attempts")
+	}
+}
+
+func TestRetryCreateJobOnlyOnRateLimit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Post(jobsPath).
+		Reply(429).
+		SetHeader("Retry-After", "0")
+
+	newGock().
+		Post(jobsPath).
+		JSON(&CreateJobRequest{Name: "my-job"}).
+		Reply(503)
+
+	newGock().
+		Post(jobsPath).
+		Reply(200).
+		JSON(&CreateJobResponse{ID: "some-id"})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithRetryPolicy(testRetryPolicy()))
+	_, err := client.CreateJob(ctx, &CreateJobRequest{Name: "my-job"})
+
+	// the 429 is retried with the same body, the 503 is not since the job
+	// may have been created
+	assertAPIError(t, err, http.StatusServiceUnavailable, ErrServer)
+
+	if gock.IsDone() {
+		t.Fatal("expected the last mock to be unused")
+	}
+}
+
+func TestRetryCreateJobNonIdempotent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Post(jobsPath).
+		Reply(503)
+
+	newGock().
+		Post(jobsPath).
+		Reply(200).
+		JSON(&CreateJobResponse{ID: "some-id"})
+
+	policy := testRetryPolicy()
+	policy.RetryNonIdempotent = true
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithRetryPolicy(policy))
+	createJobResponseWithStatus, err := client.CreateJob(ctx, &CreateJobRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if createJobResponseWithStatus.Response.ID != "some-id" {
+		t.Fatalf("unexpected id: %s", createJobResponseWithStatus.Response.ID)
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(jobsPath).
+		Reply(429).
+		SetHeader("Retry-After", "60")
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithRetryPolicy(testRetryPolicy()))
+
+	start := time.Now()
+	_, err := client.GetJobs(ctx, &GetJobsRequest{})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if time.Since(start) > 250*time.Millisecond {
+		t.Fatal("expected to give up without waiting for Retry-After")
+	}
+}
+
+func TestRetryNotOnBadRequest(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Delete(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(400)
+
+	newGock().
+		Delete(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(200)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey, WithRetryPolicy(testRetryPolicy()))
+	_, err := client.DeleteJob(ctx, &DeleteJobRequest{ID: "some-id"})
+	assertAPIError(t, err, http.StatusBadRequest, ErrBadRequest)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+	}
+
+	for retry, limit := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 300 * time.Millisecond,
+		6: 300 * time.Millisecond,
+	} {
+		wait := policy.backoff(retry, http.Header{})
+		if wait < limit/2 || wait > limit {
+			t.Fatalf("unexpected backoff for retry %d: %s", retry, wait)
+		}
+	}
+
+	wait := policy.backoff(1, http.Header{"Retry-After": []string{"2"}})
+	if wait != 2*time.Second {
+		t.Fatalf("unexpected Retry-After backoff: %s", wait)
+	}
+}
+
+func TestRetryBackoffUncapped(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond}
+
+	for retry, limit := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		4: 800 * time.Millisecond,
+	} {
+		wait := policy.backoff(retry, http.Header{})
+		if wait < limit/2 || wait > limit {
+			t.Fatalf("unexpected backoff for retry %d: %s", retry, wait)
+		}
+	}
+
+	if wait := policy.backoff(100, http.Header{}); wait <= 0 {
+		t.Fatalf("unexpected backoff after many retries: %s", wait)
+	}
+}
@@ -0,0 +1,199 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"ionq"
+)
+
+type matrix2 [2][2]complex128
+
+type matrix4 [4][4]complex128
+
+var (
+	invSqrt2 = complex(1/math.Sqrt2, 0)
+
+	pauliX = matrix2{{0, 1}, {1, 0}}
+	pauliY = matrix2{{0, -1i}, {1i, 0}}
+	pauliZ = matrix2{{1, 0}, {0, -1}}
+
+	hadamard = matrix2{{invSqrt2, invSqrt2}, {invSqrt2, -invSqrt2}}
+
+	gateS  = matrix2{{1, 0}, {0, 1i}}
+	gateSi = matrix2{{1, 0}, {0, -1i}}
+	gateT  = matrix2{{1, 0}, {0, cmplx.Exp(1i * math.Pi / 4)}}
+	gateTi = matrix2{{1, 0}, {0, cmplx.Exp(-1i * math.Pi / 4)}}
+	gateV  = matrix2{{(1 + 1i) / 2, (1 - 1i) / 2}, {(1 - 1i) / 2, (1 + 1i) / 2}}
+	gateVi = matrix2{{(1 - 1i) / 2, (1 + 1i) / 2}, {(1 + 1i) / 2, (1 - 1i) / 2}}
+
+	swap = matrix4{{1, 0, 0, 0}, {0, 0, 1, 0}, {0, 1, 0, 0}, {0, 0, 0, 1}}
+)
+
+func rx(theta float64) matrix2 {
+	c, s := complex(math.Cos(theta/2), 0), complex(math.Sin(theta/2), 0)
+	return matrix2{{c, -1i * s}, {-1i * s, c}}
+}
+
+func ry(theta float64) matrix2 {
+	c, s := complex(math.Cos(theta/2), 0), complex(math.Sin(theta/2), 0)
+	return matrix2{{c, -s}, {s, c}}
+}
+
+func rz(theta float64) matrix2 {
+	return matrix2{{cmplx.Exp(complex(0, -theta/2)), 0}, {0, cmplx.Exp(complex(0, theta/2))}}
+}
+
+// turn returns e^(i 2π phase) for a phase in turns.
+func turn(phase float64) complex128 {
+	return cmplx.Exp(complex(0, 2*math.Pi*phase))
+}
+
+func gpi(phase float64) matrix2 {
+	return matrix2{{0, turn(-phase)}, {turn(phase), 0}}
+}
+
+func gpi2(phase float64) matrix2 {
+	return matrix2{
+		{invSqrt2, -1i * turn(-phase) * invSqrt2},
+		{-1i * turn(phase) * invSqrt2, invSqrt2},
+	}
+}
+
+// ms returns the Mølmer-Sørensen gate, with the first target as the most
+// significant bit of the matrix.
+func ms(phase0, phase1, angle float64) matrix4 {
+	c := complex(math.Cos(math.Pi*angle), 0)
+	s := complex(math.Sin(math.Pi*angle), 0)
+	return matrix4{
+		{c, 0, 0, -1i * s * turn(-(phase0 + phase1))},
+		{0, c, -1i * s * turn(-(phase0 - phase1)), 0},
+		{0, -1i * s * turn(phase0-phase1), c, 0},
+		{-1i * s * turn(phase0+phase1), 0, 0, c},
+	}
+}
+
+func zz(angle float64) matrix4 {
+	minus, plus := turn(-angle/2), turn(angle/2)
+	return matrix4{
+		{minus, 0, 0, 0},
+		{0, plus, 0, 0},
+		{0, 0, plus, 0},
+		{0, 0, 0, minus},
+	}
+}
+
+// apply1 applies m to the target qubit of every basis state where all the
+// qubits of controlMask are set.
+func apply1(state []complex128, m matrix2, target uint, controlMask int) {
+	bit := 1 << target
+	for i := range state {
+		if i&bit != 0 || i&controlMask != controlMask {
+			continue
+		}
+		j := i | bit
+		a, b := state[i], state[j]
+		state[i] = m[0][0]*a + m[0][1]*b
+		state[j] = m[1][0]*a + m[1][1]*b
+	}
+}
+
+// apply2 applies m to the q0 and q1 qubits of every basis state where all
+// the qubits of controlMask are set.
+func apply2(state []complex128, m matrix4, q0, q1 uint, controlMask int) {
+	bit0, bit1 := 1<<q0, 1<<q1
+	for i := range state {
+		if i&(bit0|bit1) != 0 || i&controlMask != controlMask {
+			continue
+		}
+		idx := [4]int{i, i | bit1, i | bit0, i | bit0 | bit1}
+		var in [4]complex128
+		for k, j := range idx {
+			in[k] = state[j]
+		}
+		for r, j := range idx {
+			state[j] = m[r][0]*in[0] + m[r][1]*in[1] + m[r][2]*in[2] + m[r][3]*in[3]
+		}
+	}
+}
+
+func controlMask(gate ionq.CircuitInput) int {
+	mask := 0
+	if gate.Control != nil {
+		mask |= 1 << *gate.Control
+	}
+	for _, q := range gate.Controls {
+		mask |= 1 << q
+	}
+	return mask
+}
+
+func singleQubitMatrix(gate ionq.CircuitInput) (matrix2, bool) {
+	switch gate.Gate {
+	case ionq.GateX, ionq.GateNot, ionq.GateCNOT:
+		return pauliX, true
+	case ionq.GateY:
+		return pauliY, true
+	case ionq.GateZ:
+		return pauliZ, true
+	case ionq.GateH:
+		return hadamard, true
+	case ionq.GateS:
+		return gateS, true
+	case ionq.GateSi:
+		return gateSi, true
+	case ionq.GateT:
+		return gateT, true
+	case ionq.GateTi:
+		return gateTi, true
+	case ionq.GateV:
+		return gateV, true
+	case ionq.GateVi:
+		return gateVi, true
+	case ionq.GateRX:
+		return rx(gate.Rotation), true
+	case ionq.GateRY:
+		return ry(gate.Rotation), true
+	case ionq.GateRZ:
+		return rz(gate.Rotation), true
+	}
+	return matrix2{}, false
+}
+
+// applyGate applies a gate that has already been validated by
+// ionq.JobInput.Validate.
+func applyGate(state []complex128, gate ionq.CircuitInput, gateset string) error {
+	if gateset == ionq.GatesetNative {
+		switch gate.Gate {
+		case ionq.GateGPI:
+			apply1(state, gpi(*gate.Phase), *gate.Target, 0)
+		case ionq.GateGPI2:
+			apply1(state, gpi2(*gate.Phase), *gate.Target, 0)
+		case ionq.GateMS:
+			angle := ionq.MaxNativeAngle
+			if gate.Angle != nil {
+				angle = *gate.Angle
+			}
+			apply2(state, ms(gate.Phases[0], gate.Phases[1], angle), gate.Targets[0], gate.Targets[1], 0)
+		case ionq.GateZZ:
+			apply2(state, zz(*gate.Angle), gate.Targets[0], gate.Targets[1], 0)
+		default:
+			return fmt.Errorf("%w: native gate %q", ErrUnsupported, gate.Gate)
+		}
+		return nil
+	}
+
+	if gate.Gate == ionq.GateSwap {
+		apply2(state, swap, gate.Targets[0], gate.Targets[1], controlMask(gate))
+		return nil
+	}
+
+	m, ok := singleQubitMatrix(gate)
+	if !ok {
+		return fmt.Errorf("%w: gate %q", ErrUnsupported, gate.Gate)
+	}
+
+	apply1(state, m, *gate.Target, controlMask(gate))
+	return nil
+}
@@ -0,0 +1,299 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"ionq"
+)
+
+// DefaultTrajectories is the number of trajectories averaged for a noisy
+// request when Simulator.Trajectories is zero.
+const DefaultTrajectories = 200
+
+// ErrInvalidNoise is wrapped by the errors returned by Noise.Validate.
+var ErrInvalidNoise = errors.New("sim: invalid noise")
+
+// Noise holds the error rates of a noisy simulation.
+//
+// Noisy circuits are simulated with Monte Carlo trajectories: every gate is
+// followed by Pauli errors drawn from the depolarizing rates, then by
+// amplitude damping and dephasing of its qubits over its duration. The
+// probabilities of the trajectories are averaged, and readout errors are
+// applied to the average. Gates with more than one qubit suffer the errors of
+// the gates they decompose to, as counted by ionq.CountGates.
+type Noise struct {
+	// OneQ and TwoQ are the probabilities of a random Pauli error after a
+	// one- or two-qubit gate.
+	OneQ float64
+	TwoQ float64
+
+	// Readout is the probability of reading a qubit flipped.
+	Readout float64
+
+	// T1 and T2 are the relaxation and dephasing times, in seconds. Zero
+	// disables the error.
+	T1 float64
+	T2 float64
+
+	// OneQTime and TwoQTime are the durations of one- and two-qubit gates,
+	// in seconds, over which qubits relax and dephase.
+	OneQTime float64
+	TwoQTime float64
+}
+
+// NoiseModels holds the noise applied for each ionq.NoiseModel. The rates are
+// approximations of published characterizations of the backends, not the
+// models IonQ's simulator applies, and can be replaced.
+var NoiseModels = map[ionq.NoiseModel]Noise{
+	ionq.NoiseModelIdeal:   {},
+	ionq.NoiseModelHarmony: modelNoise(ionq.TargetHarmony, 0.996, 0.966, 0.9952, 0.2),
+	ionq.NoiseModelAria1:   modelNoise(ionq.TargetAria1, 0.9995, 0.986, 0.993, 1),
+	ionq.NoiseModelAria2:   modelNoise(ionq.TargetAria2, 0.9994, 0.964, 0.994, 1),
+	ionq.NoiseModelForte1:  modelNoise(ionq.TargetForte1, 0.9998, 0.993, 0.995, 1),
+}
+
+func modelNoise(target ionq.Target, oneQ, twoQ, spam, t2 float64) Noise {
+	timing := ionq.DefaultTimings[target]
+	timing.T2 = t2
+
+	return NoiseFromCharacterization(&ionq.Characterization{
+		Fidelity: ionq.Fidelity{
+			SPAM: ionq.FidelityStat{Mean: spam},
+			OneQ: ionq.FidelityStat{Mean: oneQ},
+			TwoQ: ionq.FidelityStat{Mean: twoQ},
+		},
+		Timing: timing,
+	})
+}
+
+// NoiseFromCharacterization returns the Noise of a backend from its mean
+// fidelities and timings. Missing fidelities and timings disable the
+// matching errors.
+func NoiseFromCharacterization(c *ionq.Characterization) Noise {
+	noise := Noise{
+		OneQ:     depolarizing(c.Fidelity.OneQ.Mean, 2),
+		TwoQ:     depolarizing(c.Fidelity.TwoQ.Mean, 4),
+		T1:       c.Timing.T1,
+		T2:       c.Timing.T2,
+		OneQTime: c.Timing.OneQ,
+		TwoQTime: c.Timing.TwoQ,
+	}
+
+	if c.Fidelity.SPAM.Mean > 0 {
+		noise.Readout = 1 - c.Fidelity.SPAM.Mean
+	}
+
+	return noise
+}
+
+// depolarizing returns the probability of a Pauli error giving the average
+// gate fidelity of a gate on a system of dimension d.
+func depolarizing(fidelity float64, d float64) float64 {
+	if fidelity <= 0 {
+		return 0
+	}
+	return min(1, (1-fidelity)*(d+1)/d)
+}
+
+// IsIdeal reports whether n applies no error.
+func (n Noise) IsIdeal() bool {
+	return n.OneQ == 0 && n.TwoQ == 0 && n.Readout == 0 && n.dampingRate() == 0 && n.dephasingRate() == 0
+}
+
+// Validate checks that the rates of n are probabilities and that the times
+// are not negative.
+func (n Noise) Validate() error {
+	type value struct {
+		name string
+		v    float64
+	}
+
+	for _, p := range []value{{"one-qubit", n.OneQ}, {"two-qubit", n.TwoQ}, {"readout", n.Readout}} {
+		if p.v < 0 || p.v > 1 || math.IsNaN(p.v) {
+			return fmt.Errorf("%w: %s error rate %v is not a probability", ErrInvalidNoise, p.name, p.v)
+		}
+	}
+
+	for _, t := range []value{{"T1", n.T1}, {"T2", n.T2}, {"one-qubit gate time", n.OneQTime}, {"two-qubit gate time", n.TwoQTime}} {
+		if t.v < 0 || math.IsNaN(t.v) {
+			return fmt.Errorf("%w: %s %v must not be negative", ErrInvalidNoise, t.name, t.v)
+		}
+	}
+
+	return nil
+}
+
+func (n Noise) dampingRate() float64 {
+	if n.T1 == 0 {
+		return 0
+	}
+	return 1 / n.T1
+}
+
+// dephasingRate returns the pure dephasing rate, the part of 1/T2 not caused
+// by relaxation.
+func (n Noise) dephasingRate() float64 {
+	if n.T2 == 0 {
+		return 0
+	}
+	return max(0, 1/n.T2-n.dampingRate()/2)
+}
+
+func (s *Simulator) noise(req *ionq.CreateJobRequest) (Noise, error) {
+	if s.Noise != nil {
+		return *s.Noise, s.Noise.Validate()
+	}
+
+	model := ionq.NoiseModelIdeal
+	if req.Noise != nil && req.Noise.Model != "" {
+		model = req.Noise.Model
+	}
+
+	noise, ok := NoiseModels[model]
+	if !ok {
+		return Noise{}, fmt.Errorf("%w: noise model %q", ErrUnsupported, model)
+	}
+	return noise, noise.Validate()
+}
+
+// noisyGate is a gate with the errors following it.
+type noisyGate struct {
+	gate   ionq.CircuitInput
+	qubits []uint
+	counts ionq.GateCounts
+}
+
+// noisyProbabilities averages the probabilities of trajectories of a
+// prepared input, then applies readout errors.
+func noisyProbabilities(input ionq.JobInput, noise Noise, trajectories uint, rng *rand.Rand) ([]float64, error) {
+	gates := make([]noisyGate, len(input.Circuit))
+	for i, gate := range input.Circuit {
+		counts, err := ionq.CountGates(ionq.JobInput{Gateset: input.Gateset, Circuit: []ionq.CircuitInput{gate}})
+		if err != nil {
+			return nil, fmt.Errorf("gate %d: %w", i, err)
+		}
+		gates[i] = noisyGate{gate: gate, qubits: gate.Qubits(), counts: counts}
+	}
+
+	probs := make([]float64, 1<<input.Qubits)
+	for range trajectories {
+		state := newState(input.Qubits)
+		for i, gate := range gates {
+			if err := applyGate(state, gate.gate, input.Gateset); err != nil {
+				return nil, fmt.Errorf("gate %d: %w", i, err)
+			}
+			applyGateErrors(state, gate, noise, rng)
+		}
+
+		for i, p := range probabilities(state) {
+			probs[i] += p / float64(trajectories)
+		}
+	}
+
+	if noise.Readout > 0 {
+		for q := range input.Qubits {
+			applyReadoutError(probs, q, noise.Readout)
+		}
+	}
+
+	return probs, nil
+}
+
+var paulis = [4]matrix2{{{1, 0}, {0, 1}}, pauliX, pauliY, pauliZ}
+
+func applyGateErrors(state []complex128, gate noisyGate, noise Noise, rng *rand.Rand) {
+	n := len(gate.qubits)
+
+	if n > 1 {
+		for k := range gate.counts.TwoQ {
+			if rng.Float64() < noise.TwoQ {
+				// any of the 15 two-qubit Paulis other than the identity
+				pauli := 1 + rng.IntN(15)
+				apply1(state, paulis[pauli%4], gate.qubits[k%n], 0)
+				apply1(state, paulis[pauli/4], gate.qubits[(k+1)%n], 0)
+			}
+		}
+	}
+
+	for k := range gate.counts.OneQ {
+		if rng.Float64() < noise.OneQ {
+			apply1(state, paulis[1+rng.IntN(3)], gate.qubits[k%n], 0)
+		}
+	}
+
+	duration := float64(gate.counts.OneQ)*noise.OneQTime + float64(gate.counts.TwoQ)*noise.TwoQTime
+	if duration == 0 {
+		return
+	}
+
+	gamma := 1 - math.Exp(-duration*noise.dampingRate())
+	phaseFlip := (1 - math.Exp(-duration*noise.dephasingRate())) / 2
+	for _, q := range gate.qubits {
+		if gamma > 0 {
+			applyDamping(state, q, gamma, rng)
+		}
+		if phaseFlip > 0 && rng.Float64() < phaseFlip {
+			apply1(state, pauliZ, q, 0)
+		}
+	}
+}
+
+// applyDamping applies amplitude damping with probability gamma to qubit q,
+// either decaying it to 0 or conditioning the state on no decay.
+func applyDamping(state []complex128, q uint, gamma float64, rng *rand.Rand) {
+	bit := 1 << q
+
+	var excited float64
+	for i, amplitude := range state {
+		if i&bit != 0 {
+			excited += real(amplitude)*real(amplitude) + imag(amplitude)*imag(amplitude)
+		}
+	}
+
+	if excited == 0 {
+		return
+	}
+
+	decay := rng.Float64() < gamma*excited
+	for i := range state {
+		if i&bit == 0 {
+			continue
+		}
+		if decay {
+			state[i^bit] = state[i]
+			state[i] = 0
+		} else {
+			state[i] *= complex(math.Sqrt(1-gamma), 0)
+		}
+	}
+
+	normalize(state)
+}
+
+func normalize(state []complex128) {
+	var norm float64
+	for _, amplitude := range state {
+		norm += real(amplitude)*real(amplitude) + imag(amplitude)*imag(amplitude)
+	}
+
+	scale := complex(1/math.Sqrt(norm), 0)
+	for i := range state {
+		state[i] *= scale
+	}
+}
+
+// applyReadoutError flips the reading of qubit q with probability p.
+func applyReadoutError(probs []float64, q uint, p float64) {
+	bit := 1 << q
+	for i := range probs {
+		if i&bit != 0 {
+			continue
+		}
+		a, b := probs[i], probs[i|bit]
+		probs[i] = (1-p)*a + p*b
+		probs[i|bit] = p*a + (1-p)*b
+	}
+}
@@ -0,0 +1,118 @@
+package sim
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"ionq"
+)
+
+func TestRunIdealNoiseModel(t *testing.T) {
+	input := mustBuild(t, ionq.NewCircuit(2).H(0).CNOT(0, 1))
+
+	s := &Simulator{}
+	out, err := s.Run(&ionq.CreateJobRequest{Input: input, Noise: &ionq.NoiseInput{Model: ionq.NoiseModelIdeal}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutput(t, ionq.GetJobOutputResponse{"0": 0.5, "3": 0.5}, out)
+}
+
+func TestRunReadoutError(t *testing.T) {
+	input := mustBuild(t, ionq.NewCircuit(2).X(0))
+
+	s := &Simulator{Noise: &Noise{Readout: 0.1}}
+	out, err := s.Run(&ionq.CreateJobRequest{Input: input})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutput(t, ionq.GetJobOutputResponse{"0": 0.09, "1": 0.81, "2": 0.01, "3": 0.09}, out)
+}
+
+func TestRunAmplitudeDamping(t *testing.T) {
+	input := mustBuild(t, ionq.NewCircuit(1).X(0))
+
+	// the X gate lasts one T1, leaving the qubit excited with probability 1/e
+	s := &Simulator{Noise: &Noise{T1: 1, OneQTime: 1}, Trajectories: 4000}
+	out, err := s.Run(&ionq.CreateJobRequest{Input: input})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p := float64(out["1"]); math.Abs(p-1/math.E) > 0.03 {
+		t.Fatalf("unexpected excited probability: %v, expected about %v", p, 1/math.E)
+	}
+}
+
+func TestRunNoiseModelSeeded(t *testing.T) {
+	input := mustBuild(t, ionq.NewCircuit(3).H(0).CNOT(0, 1).CNOT(1, 2))
+	req := &ionq.CreateJobRequest{Input: input, Shots: 1000, Noise: &ionq.NoiseInput{Model: ionq.NoiseModelHarmony, Seed: 3}}
+
+	s := &Simulator{}
+	first, err := s.Sample(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := s.Sample(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutput(t, first, second)
+
+	if first["0"]+first["7"] >= 1 {
+		t.Fatalf("expected noise outside the GHZ states: %v", first)
+	}
+
+	if first["0"] < 0.3 || first["7"] < 0.3 {
+		t.Fatalf("expected the GHZ states to dominate: %v", first)
+	}
+}
+
+func TestNoiseFromCharacterization(t *testing.T) {
+	noise := NoiseFromCharacterization(&ionq.Characterization{
+		Fidelity: ionq.Fidelity{
+			SPAM: ionq.FidelityStat{Mean: 0.99},
+			OneQ: ionq.FidelityStat{Mean: 0.998},
+			TwoQ: ionq.FidelityStat{Mean: 0.96},
+		},
+		Timing: ionq.Timing{T1: 10, T2: 1, OneQ: 0.0001, TwoQ: 0.0006},
+	})
+
+	expected := Noise{OneQ: 0.003, TwoQ: 0.05, Readout: 0.01, T1: 10, T2: 1, OneQTime: 0.0001, TwoQTime: 0.0006}
+	for name, pair := range map[string][2]float64{
+		"OneQ":    {expected.OneQ, noise.OneQ},
+		"TwoQ":    {expected.TwoQ, noise.TwoQ},
+		"Readout": {expected.Readout, noise.Readout},
+	} {
+		if math.Abs(pair[0]-pair[1]) > 1e-9 {
+			t.Fatalf("unexpected %s: %v, expected %v", name, pair[1], pair[0])
+		}
+	}
+
+	if noise.T1 != expected.T1 || noise.T2 != expected.T2 || noise.OneQTime != expected.OneQTime || noise.TwoQTime != expected.TwoQTime {
+		t.Fatalf("unexpected timings: %+v", noise)
+	}
+
+	if !NoiseFromCharacterization(&ionq.Characterization{}).IsIdeal() {
+		t.Fatal("expected an empty characterization to be ideal")
+	}
+}
+
+func TestInvalidNoise(t *testing.T) {
+	input := mustBuild(t, ionq.NewCircuit(1).H(0))
+
+	s := &Simulator{Noise: &Noise{OneQ: 1.5}}
+	if _, err := s.Run(&ionq.CreateJobRequest{Input: input}); !errors.Is(err, ErrInvalidNoise) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s = &Simulator{}
+	if _, err := s.Run(&ionq.CreateJobRequest{Input: input, Noise: &ionq.NoiseInput{Model: "unknown"}}); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+// Package sim is an in-process statevector simulator for IonQ circuits. It
+// runs the same JobInput sent with ionq.Client.CreateJob, for both the QIS
+// and native gate sets, so circuits can be tested without network access.
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+
+	"ionq"
+	"ionq/qasm"
+)
+
+// MaxQubits is the largest number of qubits the simulator accepts.
+const MaxQubits = 20
+
+// probabilityEpsilon is the probability under which a basis state is left
+// out of the results, as done by the IonQ API.
+const probabilityEpsilon = 1e-12
+
+var (
+	// ErrTooManyQubits is returned for circuits larger than MaxQubits.
+	ErrTooManyQubits = errors.New("sim: too many qubits")
+
+	// ErrUnsupported is returned for inputs the simulator cannot run.
+	ErrUnsupported = errors.New("sim: unsupported input")
+)
+
+// Simulator runs CreateJobRequests locally.
+type Simulator struct {
+	// Seed seeds the sampling of shots and noise when the request does not
+	// set a noise seed.
+	Seed uint64
+
+	// Noise overrides the noise of the request's noise model, for example
+	// with NoiseFromCharacterization. Without it, requests are simulated
+	// with the NoiseModels entry of their model, ideal by default.
+	Noise *Noise
+
+	// Trajectories is the number of noisy trajectories averaged for a
+	// request, DefaultTrajectories when zero.
+	Trajectories uint
+}
+
+// State returns the statevector of the circuit, where qubit 0 is the least
+// significant bit of the basis state index. OpenQASM inputs are converted
+// with the qasm package first.
+func State(input ionq.JobInput) ([]complex128, error) {
+	input, err := prepare(input)
+	if err != nil {
+		return nil, err
+	}
+
+	state := newState(input.Qubits)
+	for i, gate := range input.Circuit {
+		if err := applyGate(state, gate, input.Gateset); err != nil {
+			return nil, fmt.Errorf("gate %d: %w", i, err)
+		}
+	}
+
+	return state, nil
+}
+
+// prepare converts and validates an input the simulator can run.
+func prepare(input ionq.JobInput) (ionq.JobInput, error) {
+	if input.Format == ionq.FormatOpenQASM {
+		var err error
+		if input, err = qasm.Parse(input.Data); err != nil {
+			return ionq.JobInput{}, err
+		}
+	}
+
+	if err := input.Validate(); err != nil {
+		return ionq.JobInput{}, err
+	}
+
+	if len(input.Circuits) > 0 {
+		return ionq.JobInput{}, fmt.Errorf("%w: multicircuit input, run each circuit on its own", ErrUnsupported)
+	}
+
+	if input.Qubits > MaxQubits {
+		return ionq.JobInput{}, fmt.Errorf("%w: %d qubits, the maximum is %d", ErrTooManyQubits, input.Qubits, MaxQubits)
+	}
+
+	return input, nil
+}
+
+func newState(qubits uint) []complex128 {
+	state := make([]complex128, 1<<qubits)
+	state[0] = 1
+	return state
+}
+
+// Probabilities returns the exact probability of every basis state of the
+// circuit, keyed like the IonQ API results: by the decimal index of the basis
+// state with qubit 0 as the least significant bit. States with a zero
+// probability are left out.
+func Probabilities(input ionq.JobInput) (ionq.GetJobOutputResponse, error) {
+	state, err := State(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return output(probabilities(state)), nil
+}
+
+func probabilities(state []complex128) []float64 {
+	probs := make([]float64, len(state))
+	for i, amplitude := range state {
+		probs[i] = real(amplitude)*real(amplitude) + imag(amplitude)*imag(amplitude)
+	}
+	return probs
+}
+
+func output(probs []float64) ionq.GetJobOutputResponse {
+	out := make(ionq.GetJobOutputResponse)
+	for i, p := range probs {
+		if p > probabilityEpsilon {
+			out[strconv.Itoa(i)] = float32(p)
+		}
+	}
+	return out
+}
+
+// Run returns the probabilities of the request's circuit. They are exact
+// with the ideal noise model, and averaged over noisy trajectories otherwise,
+// see Simulator.Noise.
+func (s *Simulator) Run(req *ionq.CreateJobRequest) (ionq.GetJobOutputResponse, error) {
+	probs, err := s.probabilities(req, s.rand(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return output(probs), nil
+}
+
+// Sample returns the probabilities measured over req.Shots shots. The
+// sampling is deterministic for a given seed, which is the request's noise
+// seed when set and the Simulator's Seed otherwise.
+func (s *Simulator) Sample(req *ionq.CreateJobRequest) (ionq.GetJobOutputResponse, error) {
+	if req.Shots == 0 {
+		return nil, fmt.Errorf("%w: sampling requires shots", ErrUnsupported)
+	}
+
+	rng := s.rand(req)
+	probs, err := s.probabilities(req, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := sample(probs, req.Shots, rng)
+
+	probs = make([]float64, len(probs))
+	for i, count := range counts {
+		probs[i] = float64(count) / float64(req.Shots)
+	}
+
+	return output(probs), nil
+}
+
+func (s *Simulator) probabilities(req *ionq.CreateJobRequest, rng *rand.Rand) ([]float64, error) {
+	noise, err := s.noise(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if noise.IsIdeal() {
+		state, err := State(req.Input)
+		if err != nil {
+			return nil, err
+		}
+		return probabilities(state), nil
+	}
+
+	input, err := prepare(req.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	trajectories := s.Trajectories
+	if trajectories == 0 {
+		trajectories = DefaultTrajectories
+	}
+
+	return noisyProbabilities(input, noise, trajectories, rng)
+}
+
+func (s *Simulator) rand(req *ionq.CreateJobRequest) *rand.Rand {
+	seed := s.Seed
+	if req.Noise != nil && req.Noise.Seed != 0 {
+		seed = uint64(req.Noise.Seed)
+	}
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+// sample returns how many of the shots measured each basis state.
+func sample(probs []float64, shots uint, rng *rand.Rand) []uint {
+	cumulative := make([]float64, len(probs))
+	total := 0.0
+	for i, p := range probs {
+		total += p
+		cumulative[i] = total
+	}
+
+	counts := make([]uint, len(probs))
+	for range shots {
+		r := rng.Float64() * total
+		i := min(sort.SearchFloat64s(cumulative, r), len(probs)-1)
+		// skip states with no probability sharing the same cumulative value
+		for probs[i] == 0 && i < len(probs)-1 {
+			i++
+		}
+		counts[i]++
+	}
+
+	return counts
+}
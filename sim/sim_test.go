@@ -0,0 +1,339 @@
+package sim
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"strings"
+	"testing"
+
+	"ionq"
+)
+
+func assertOutput(t *testing.T, expected, actual ionq.GetJobOutputResponse) {
+	t.Helper()
+
+	if len(expected) != len(actual) {
+		t.Fatalf("unexpected output: %v, expected: %v", actual, expected)
+	}
+
+	for state, p := range expected {
+		if math.Abs(float64(actual[state]-p)) > 1e-6 {
+			t.Fatalf("unexpected probability for %s: %v, expected: %v", state, actual[state], p)
+		}
+	}
+}
+
+type builder interface {
+	Build() (ionq.JobInput, error)
+}
+
+func mustBuild(t *testing.T, b builder) ionq.JobInput {
+	t.Helper()
+
+	input, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+func TestProbabilitiesBellState(t *testing.T) {
+	input := mustBuild(t, ionq.NewCircuit(2).H(0).CNOT(0, 1))
+
+	out, err := Probabilities(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutput(t, ionq.GetJobOutputResponse{"0": 0.5, "3": 0.5}, out)
+}
+
+func TestProbabilitiesQubitOrdering(t *testing.T) {
+	// qubit 0 is the least significant bit
+	input := mustBuild(t, ionq.NewCircuit(3).X(1))
+
+	out, err := Probabilities(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutput(t, ionq.GetJobOutputResponse{"2": 1}, out)
+}
+
+func TestProbabilitiesQISGates(t *testing.T) {
+	for name, tc := range map[string]struct {
+		circuit  *ionq.Circuit
+		expected ionq.GetJobOutputResponse
+	}{
+		"toffoli": {
+			ionq.NewCircuit(3).X(0).X(1).Toffoli(0, 1, 2),
+			ionq.GetJobOutputResponse{"7": 1},
+		},
+		"swap": {
+			ionq.NewCircuit(2).X(0).Swap(0, 1),
+			ionq.GetJobOutputResponse{"2": 1},
+		},
+		"v twice is x": {
+			ionq.NewCircuit(1).V(0).V(0),
+			ionq.GetJobOutputResponse{"1": 1},
+		},
+		"phases cancel": {
+			ionq.NewCircuit(1).H(0).S(0).T(0).Ti(0).Si(0).H(0),
+			ionq.GetJobOutputResponse{"0": 1},
+		},
+		"ry": {
+			ionq.NewCircuit(1).RY(0, math.Pi/2),
+			ionq.GetJobOutputResponse{"0": 0.5, "1": 0.5},
+		},
+		"rx pi": {
+			ionq.NewCircuit(1).RX(0, math.Pi).RZ(0, 1.2),
+			ionq.GetJobOutputResponse{"1": 1},
+		},
+		"y z": {
+			ionq.NewCircuit(1).Y(0).Z(0).Vi(0).V(0),
+			ionq.GetJobOutputResponse{"1": 1},
+		},
+		"controlled not triggered": {
+			ionq.NewCircuit(2).CNOT(0, 1),
+			ionq.GetJobOutputResponse{"0": 1},
+		},
+	} {
+		input := mustBuild(t, tc.circuit)
+
+		out, err := Probabilities(input)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		assertOutput(t, tc.expected, out)
+	}
+}
+
+func TestProbabilitiesNativeGates(t *testing.T) {
+	for name, tc := range map[string]struct {
+		circuit  *ionq.NativeCircuit
+		expected ionq.GetJobOutputResponse
+	}{
+		"gpi is a bit flip": {
+			ionq.NewNativeCircuit(1).GPI(0, 0.3),
+			ionq.GetJobOutputResponse{"1": 1},
+		},
+		"gpi2 is a half flip": {
+			ionq.NewNativeCircuit(1).GPI2(0, 0.1),
+			ionq.GetJobOutputResponse{"0": 0.5, "1": 0.5},
+		},
+		"ms entangles": {
+			ionq.NewNativeCircuit(2).MS(0, 1, 0, 0),
+			ionq.GetJobOutputResponse{"0": 0.5, "3": 0.5},
+		},
+		"two ms flip both": {
+			ionq.NewNativeCircuit(2).MS(0, 1, 0, 0).MS(0, 1, 0, 0),
+			ionq.GetJobOutputResponse{"3": 1},
+		},
+		"partial ms": {
+			ionq.NewNativeCircuit(2).PartialMS(0, 1, 0.2, 0.7, 0.125),
+			ionq.GetJobOutputResponse{
+				"0": float32(math.Pow(math.Cos(math.Pi/8), 2)),
+				"3": float32(math.Pow(math.Sin(math.Pi/8), 2)),
+			},
+		},
+		"zz only adds phases": {
+			ionq.NewNativeCircuit(2).GPI2(0, 0).GPI2(1, 0).ZZ(0, 1, 0.25).GPI2(0, 0.5).GPI2(1, 0.5),
+			ionq.GetJobOutputResponse{"0": 0.5, "3": 0.5},
+		},
+	} {
+		input := mustBuild(t, tc.circuit)
+
+		out, err := Probabilities(input)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		assertOutput(t, tc.expected, out)
+	}
+}
+
+func TestSampleDeterministic(t *testing.T) {
+	input := mustBuild(t, ionq.NewCircuit(3).H(0).H(1).H(2))
+	req := &ionq.CreateJobRequest{Input: input, Shots: 1000}
+
+	s := &Simulator{Seed: 42}
+
+	first, err := s.Sample(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := s.Sample(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutput(t, first, second)
+
+	total := float32(0)
+	for _, p := range first {
+		total += p
+	}
+
+	if len(first) != 8 || math.Abs(float64(total-1)) > 1e-6 {
+		t.Fatalf("unexpected sample: %v", first)
+	}
+
+	req.Noise = &ionq.NoiseInput{Seed: 7}
+	third, err := s.Sample(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	same := true
+	for state, p := range first {
+		if third[state] != p {
+			same = false
+		}
+	}
+
+	if same {
+		t.Fatal("expected the noise seed to change the sample")
+	}
+}
+
+func TestSampleSkipsImpossibleStates(t *testing.T) {
+	input := mustBuild(t, ionq.NewCircuit(2).X(1))
+
+	s := &Simulator{}
+	out, err := s.Sample(&ionq.CreateJobRequest{Input: input, Shots: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutput(t, ionq.GetJobOutputResponse{"2": 1}, out)
+}
+
+func TestTooManyQubits(t *testing.T) {
+	input := mustBuild(t, ionq.NewCircuit(MaxQubits+1).H(0))
+
+	s := &Simulator{}
+	if _, err := s.Run(&ionq.CreateJobRequest{Input: input}); !errors.Is(err, ErrTooManyQubits) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestInvalidInput(t *testing.T) {
+	input := ionq.JobInput{
+		Qubits:  1,
+		Circuit: []ionq.CircuitInput{{Gate: "u3"}},
+	}
+
+	if _, err := Probabilities(input); !errors.Is(err, ionq.ErrInvalidCircuit) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestProbabilitiesOpenQASM(t *testing.T) {
+	input := ionq.NewQASMInput(`
+OPENQASM 2.0;
+qreg q[3];
+x q[0];
+x q[1];
+h q[1];
+cp(pi) q[0], q[1];
+h q[1];
+u3(pi, 0, pi) q[2];
+u2(0, pi) q[2];
+u2(0, pi) q[2];
+`)
+
+	out, err := Probabilities(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertOutput(t, ionq.GetJobOutputResponse{"5": 1}, out)
+}
+
+func TestNativeQASMDefinitions(t *testing.T) {
+	input := mustBuild(t, ionq.NewNativeCircuit(3).
+		GPI2(0, 0.1).GPI(1, 0.3).GPI2(2, 0.7).
+		MS(0, 1, 0.2, 0.6).
+		PartialMS(1, 2, 0.05, 0.4, 0.1).
+		ZZ(0, 2, 0.15).
+		GPI2(0, 0.35).GPI2(2, 0.8))
+
+	expected, err := Probabilities(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program, err := input.ToQASM(ionq.QASM2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// renaming the gates makes the importer expand the exported definitions
+	// instead of using the native gates
+	renamed := program
+	for _, gate := range []string{"gpi2", "gpi", "ms", "zz"} {
+		renamed = strings.ReplaceAll(renamed, gate+"(", "my"+gate+"(")
+	}
+
+	out, err := Probabilities(ionq.NewQASMInput(renamed))
+	if err != nil {
+		t.Fatalf("%s\n%s", err, renamed)
+	}
+
+	assertOutput(t, expected, out)
+}
+
+func TestTwoQubitRotationDecompositions(t *testing.T) {
+	const theta = 0.9
+	prep := "OPENQASM 2.0;\nqreg q[2];\nrx(0.3) q[0];\nry(0.7) q[1];\nrz(1.1) q[0];\n"
+
+	pauli := map[string]matrix2{"rxx": pauliX, "ryy": pauliY, "rzz": pauliZ}
+
+	for gate, p := range pauli {
+		expected, err := State(ionq.NewQASMInput(prep))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// exp(-iθ/2 P⊗P) = cos(θ/2) I - i sin(θ/2) P⊗P
+		var m matrix4
+		c, s := complex(math.Cos(theta/2), 0), complex(math.Sin(theta/2), 0)
+		for r := range 4 {
+			for col := range 4 {
+				m[r][col] = -1i * s * p[r/2][col/2] * p[r%2][col%2]
+				if r == col {
+					m[r][col] += c
+				}
+			}
+		}
+		apply2(expected, m, 0, 1, 0)
+
+		actual, err := State(ionq.NewQASMInput(prep + gate + "(0.9) q[0], q[1];\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		overlap := complex(0, 0)
+		for i := range expected {
+			overlap += cmplx.Conj(expected[i]) * actual[i]
+		}
+
+		if math.Abs(cmplx.Abs(overlap)-1) > 1e-9 {
+			t.Fatalf("%s: unexpected overlap %v", gate, overlap)
+		}
+	}
+}
+
+func TestMultiCircuitUnsupported(t *testing.T) {
+	input := ionq.JobInput{
+		Qubits:   1,
+		Circuits: []ionq.NamedCircuit{{Name: "h", Circuit: []ionq.CircuitInput{{Gate: ionq.GateH, Target: new(uint)}}}},
+	}
+
+	if _, err := Probabilities(input); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package ionq
+
+import (
+	"errors"
+	"fmt"
+)
+
+// JobStatus is the status of a job as reported by the IonQ API.
+//
+// Decoding a JobStatus never fails so that statuses added to the API in the
+// future do not break existing callers; use IsKnown to detect them. Statuses
+// sent to the API, such as the GetJobs filter, are validated instead.
+type JobStatus string
+
+const (
+	JobStatusSubmitted JobStatus = "submitted"
+	JobStatusReady     JobStatus = "ready"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCanceled  JobStatus = "canceled"
+)
+
+// ErrUnknownJobStatus is returned when a JobStatus is not one of the known
+// statuses.
+var ErrUnknownJobStatus = errors.New("ionq: unknown job status")
+
+// jobStatusOrder gives the position of each status in a job's lifecycle.
+// Terminal statuses share the last position.
+var jobStatusOrder = map[JobStatus]int{
+	JobStatusSubmitted: 0,
+	JobStatusReady:     1,
+	JobStatusRunning:   2,
+	JobStatusCompleted: 3,
+	JobStatusFailed:    3,
+	JobStatusCanceled:  3,
+}
+
+// IsKnown reports whether s is one of the statuses defined by this package.
+func (s JobStatus) IsKnown() bool {
+	_, ok := jobStatusOrder[s]
+	return ok
+}
+
+// IsTerminal reports whether a job with this status will not change status
+// anymore.
+func (s JobStatus) IsTerminal() bool {
+	switch s {
+	case JobStatusCompleted, JobStatusFailed, JobStatusCanceled:
+		return true
+	}
+	return false
+}
+
+// Validate returns ErrUnknownJobStatus if s is not a known status.
+func (s JobStatus) Validate() error {
+	if !s.IsKnown() {
+		return fmt.Errorf("%w: %q", ErrUnknownJobStatus, string(s))
+	}
+	return nil
+}
+
+// CanTransitionTo reports whether a job may go from status s to next. Since
+// jobs are observed by polling, intermediate statuses may be skipped, so any
+// move forward in the lifecycle is allowed, as is observing the same status
+// again. Terminal statuses cannot be left and unknown statuses never
+// transition.
+func (s JobStatus) CanTransitionTo(next JobStatus) bool {
+	from, ok := jobStatusOrder[s]
+	if !ok {
+		return false
+	}
+
+	to, ok := jobStatusOrder[next]
+	if !ok {
+		return false
+	}
+
+	if s == next {
+		return true
+	}
+
+	return !s.IsTerminal() && to > from
+}
@@ -0,0 +1,77 @@
+package ionq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJobStatusIsTerminal(t *testing.T) {
+	for status, terminal := range map[JobStatus]bool{
+		JobStatusSubmitted: false,
+		JobStatusReady:     false,
+		JobStatusRunning:   false,
+		JobStatusCompleted: true,
+		JobStatusFailed:    true,
+		JobStatusCanceled:  true,
+		"paused":           false,
+	} {
+		if status.IsTerminal() != terminal {
+			t.Fatalf("unexpected IsTerminal for %q", status)
+		}
+	}
+}
+
+func TestJobStatusCanTransitionTo(t *testing.T) {
+	for _, tc := range []struct {
+		from, to JobStatus
+		allowed  bool
+	}{
+		{JobStatusSubmitted, JobStatusReady, true},
+		{JobStatusSubmitted, JobStatusCompleted, true},
+		{JobStatusReady, JobStatusRunning, true},
+		{JobStatusRunning, JobStatusRunning, true},
+		{JobStatusRunning, JobStatusCanceled, true},
+		{JobStatusRunning, JobStatusReady, false},
+		{JobStatusCompleted, JobStatusFailed, false},
+		{JobStatusCanceled, JobStatusRunning, false},
+		{JobStatusSubmitted, "paused", false},
+		{"paused", JobStatusCompleted, false},
+	} {
+		if tc.from.CanTransitionTo(tc.to) != tc.allowed {
+			t.Fatalf("unexpected CanTransitionTo from %q to %q", tc.from, tc.to)
+		}
+	}
+}
+
+func TestJobStatusUnknownDecodes(t *testing.T) {
+	var job Job
+	if err := json.Unmarshal([]byte(`{"id":"some-id","status":"paused"}`), &job); err != nil {
+		t.Fatal(err)
+	}
+
+	if job.Status.IsKnown() {
+		t.Fatalf("expected %q to be flagged as unknown", job.Status)
+	}
+
+	if !errors.Is(job.Status.Validate(), ErrUnknownJobStatus) {
+		t.Fatalf("expected ErrUnknownJobStatus")
+	}
+
+	if err := JobStatusRunning.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetJobsRejectsUnknownStatus(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := client.GetJobs(ctx, &GetJobsRequest{Status: "complete"})
+	if !errors.Is(err, ErrUnknownJobStatus) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
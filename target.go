@@ -0,0 +1,159 @@
+package ionq
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Target is the backend a job runs on.
+//
+// Like JobStatus, decoding a Target never fails so that backends added to
+// the API do not break existing callers; CreateJobRequest.Validate rejects
+// unknown targets instead.
+type Target string
+
+const (
+	TargetSimulator        Target = "simulator"
+	TargetHarmony          Target = "qpu.harmony"
+	TargetAria1            Target = "qpu.aria-1"
+	TargetAria2            Target = "qpu.aria-2"
+	TargetForte1           Target = "qpu.forte-1"
+	TargetForteEnterprise1 Target = "qpu.forte-enterprise-1"
+)
+
+// NoiseModel is the noise model the simulator target applies to a job.
+type NoiseModel string
+
+const (
+	NoiseModelIdeal   NoiseModel = "ideal"
+	NoiseModelHarmony NoiseModel = "harmony"
+	NoiseModelAria1   NoiseModel = "aria-1"
+	NoiseModelAria2   NoiseModel = "aria-2"
+	NoiseModelForte1  NoiseModel = "forte-1"
+)
+
+var (
+	// ErrUnknownTarget is returned when a Target is not one of the known
+	// targets.
+	ErrUnknownTarget = errors.New("ionq: unknown target")
+
+	// ErrUnknownNoiseModel is returned when a NoiseModel is not one of the
+	// known noise models.
+	ErrUnknownNoiseModel = errors.New("ionq: unknown noise model")
+
+	// ErrInvalidJobRequest is wrapped by the errors returned by
+	// CreateJobRequest.Validate.
+	ErrInvalidJobRequest = errors.New("ionq: invalid job request")
+)
+
+// targetLimits holds the largest circuit and number of shots each target
+// accepts, as documented by the IonQ API.
+var targetLimits = map[Target]struct {
+	qubits   uint
+	maxShots uint
+}{
+	TargetSimulator:        {29, 1_000_000},
+	TargetHarmony:          {11, 10_000},
+	TargetAria1:            {25, 10_000},
+	TargetAria2:            {25, 10_000},
+	TargetForte1:           {36, 10_000},
+	TargetForteEnterprise1: {36, 10_000},
+}
+
+var noiseModels = []NoiseModel{NoiseModelIdeal, NoiseModelHarmony, NoiseModelAria1, NoiseModelAria2, NoiseModelForte1}
+
+// IsKnown reports whether t is one of the targets defined by this package.
+func (t Target) IsKnown() bool {
+	_, ok := targetLimits[t]
+	return ok
+}
+
+// IsQPU reports whether t runs jobs on quantum hardware.
+func (t Target) IsQPU() bool {
+	return t.IsKnown() && t != TargetSimulator
+}
+
+// Qubits returns the number of qubits available on t, 0 for unknown
+// targets.
+func (t Target) Qubits() uint {
+	return targetLimits[t].qubits
+}
+
+// MaxShots returns the largest number of shots a job on t can request, 0
+// for unknown targets.
+func (t Target) MaxShots() uint {
+	return targetLimits[t].maxShots
+}
+
+// Validate returns ErrUnknownTarget if t is not a known target.
+func (t Target) Validate() error {
+	if !t.IsKnown() {
+		return fmt.Errorf("%w: %q", ErrUnknownTarget, string(t))
+	}
+	return nil
+}
+
+// IsKnown reports whether m is one of the noise models defined by this
+// package.
+func (m NoiseModel) IsKnown() bool {
+	return slices.Contains(noiseModels, m)
+}
+
+// Validate returns ErrUnknownNoiseModel if m is not a known noise model.
+func (m NoiseModel) Validate() error {
+	if !m.IsKnown() {
+		return fmt.Errorf("%w: %q", ErrUnknownNoiseModel, string(m))
+	}
+	return nil
+}
+
+// Validate checks the request before it is sent: the target and noise model
+// must be known, noise models are only accepted by the simulator, the shots
+// and the circuit's qubits must fit the target, and the metadata and input
+// must be valid.
+// An empty target is the simulator, as for the API.
+//
+// CreateJob does not call Validate, so requests for targets added to the API
+// after this package can still be sent.
+func (r *CreateJobRequest) Validate() error {
+	target := r.Target
+	if target == "" {
+		target = TargetSimulator
+	}
+
+	if err := target.Validate(); err != nil {
+		return err
+	}
+
+	if r.Noise != nil && r.Noise.Model != "" {
+		if err := r.Noise.Model.Validate(); err != nil {
+			return err
+		}
+		if target != TargetSimulator {
+			return fmt.Errorf("%w: noise model %q requires the %s target, found %s", ErrInvalidJobRequest, r.Noise.Model, TargetSimulator, target)
+		}
+	}
+
+	if err := r.Metadata.Validate(); err != nil {
+		return err
+	}
+
+	if r.Shots > target.MaxShots() {
+		return fmt.Errorf("%w: %d shots, %s accepts at most %d", ErrInvalidJobRequest, r.Shots, target, target.MaxShots())
+	}
+
+	if r.Input.Format == "" && r.Input.Circuit == nil && r.Input.Circuits == nil {
+		return fmt.Errorf("%w: missing input", ErrInvalidJobRequest)
+	}
+
+	if err := r.Input.Validate(); err != nil {
+		return err
+	}
+
+	if r.Input.Qubits > target.Qubits() {
+		return fmt.Errorf("%w: circuit uses %d qubits, %s has %d", ErrInvalidJobRequest, r.Input.Qubits, target, target.Qubits())
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package ionq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTargetIsKnown(t *testing.T) {
+	for _, target := range []Target{TargetSimulator, TargetHarmony, TargetAria1, TargetAria2, TargetForte1, TargetForteEnterprise1} {
+		if err := target.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Target("qpu.ariaa").Validate(); !errors.Is(err, ErrUnknownTarget) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if TargetSimulator.IsQPU() || !TargetAria1.IsQPU() || Target("qpu.ariaa").IsQPU() {
+		t.Fatal("unexpected IsQPU result")
+	}
+}
+
+func TestNoiseModelIsKnown(t *testing.T) {
+	if err := NoiseModelAria1.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NoiseModel("aria").Validate(); !errors.Is(err, ErrUnknownNoiseModel) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCreateJobRequestValidate(t *testing.T) {
+	small, err := NewCircuit(2).H(0).CNOT(0, 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	large, err := NewCircuit(12).H(11).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		request  CreateJobRequest
+		expected error
+	}{
+		{"default target", CreateJobRequest{Input: small}, nil},
+		{"qpu", CreateJobRequest{Target: TargetAria1, Shots: 1000, Input: large}, nil},
+		{"noisy simulator", CreateJobRequest{Target: TargetSimulator, Noise: &NoiseInput{Model: NoiseModelHarmony}, Input: small}, nil},
+		{"qasm", CreateJobRequest{Target: TargetHarmony, Input: NewQASMInput("OPENQASM 2.0;")}, nil},
+		{"unknown target", CreateJobRequest{Target: "qpu.ariaa", Input: small}, ErrUnknownTarget},
+		{"unknown noise model", CreateJobRequest{Noise: &NoiseInput{Model: "aria"}, Input: small}, ErrUnknownNoiseModel},
+		{"noise model on qpu", CreateJobRequest{Target: TargetAria1, Noise: &NoiseInput{Model: NoiseModelAria1}, Input: small}, ErrInvalidJobRequest},
+		{"too many shots", CreateJobRequest{Target: TargetForte1, Shots: 10_001, Input: small}, ErrInvalidJobRequest},
+		{"too many qubits", CreateJobRequest{Target: TargetHarmony, Input: large}, ErrInvalidJobRequest},
+		{"missing input", CreateJobRequest{Target: TargetHarmony}, ErrInvalidJobRequest},
+		{"invalid circuit", CreateJobRequest{Input: JobInput{Qubits: 1, Circuit: []CircuitInput{{Gate: "u3"}}}}, ErrInvalidCircuit},
+	} {
+		err := tc.request.Validate()
+		if tc.expected == nil && err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if tc.expected != nil && !errors.Is(err, tc.expected) {
+			t.Fatalf("%s: expected %v, received %v", tc.name, tc.expected, err)
+		}
+	}
+}
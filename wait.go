@@ -0,0 +1,135 @@
+package ionq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Sentinel errors that can be matched against a *JobError with errors.Is.
+var (
+	ErrJobFailed   = errors.New("ionq: job failed")
+	ErrJobCanceled = errors.New("ionq: job canceled")
+)
+
+// JobError is returned by WaitForJob when a job ends in the failed or
+// canceled state.
+type JobError struct {
+	Job     Job
+	Status  JobStatus
+	Code    string
+	Message string
+}
+
+func (e *JobError) Error() string {
+	msg := fmt.Sprintf("ionq: job %s %s", e.Job.ID, e.Status)
+	if e.Code != "" {
+		msg += fmt.Sprintf(" (%s)", e.Code)
+	}
+	if e.Message != "" {
+		msg += fmt.Sprintf(": %s", e.Message)
+	}
+	return msg
+}
+
+// Is reports whether the JobError matches ErrJobFailed or ErrJobCanceled.
+func (e *JobError) Is(target error) bool {
+	switch target {
+	case ErrJobFailed:
+		return e.Status == JobStatusFailed
+	case ErrJobCanceled:
+		return e.Status == JobStatusCanceled
+	}
+	return false
+}
+
+// WaitOptions configures how WaitForJob polls a job. The zero value uses
+// the defaults described on each field.
+type WaitOptions struct {
+	// Interval is the wait between the first polls, defaults to 1 second.
+	Interval time.Duration
+
+	// MaxInterval caps the wait between polls, defaults to 30 seconds.
+	MaxInterval time.Duration
+
+	// Multiplier is applied to the interval after each poll, defaults to 1.5.
+	// Use 1 to poll at a constant interval.
+	Multiplier float64
+}
+
+func (o *WaitOptions) withDefaults() WaitOptions {
+	var opts WaitOptions
+	if o != nil {
+		opts = *o
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = 30 * time.Second
+	}
+
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = opts.Interval
+	}
+
+	if opts.Multiplier < 1 {
+		opts.Multiplier = 1.5
+	}
+
+	return opts
+}
+
+// WaitForJob polls the job with the given ID until it reaches a terminal
+// state or ctx is done. The completed job is returned, while failed and
+// canceled jobs are returned as a *JobError. The first wait honours the job's
+// PredictedExecutionTime when it is longer than the poll interval.
+func (c *Client) WaitForJob(ctx context.Context, id string, opts *WaitOptions) (*Job, error) {
+	waitOptions := opts.withDefaults()
+	interval := waitOptions.Interval
+
+	for poll := 0; ; poll++ {
+		jobResponseWithStatus, err := c.GetJob(ctx, &GetJobRequest{ID: id})
+		if err != nil {
+			return nil, err
+		}
+
+		job := Job(jobResponseWithStatus.Response)
+
+		switch job.Status {
+		case JobStatusCompleted:
+			return &job, nil
+		case JobStatusFailed, JobStatusCanceled:
+			return &job, &JobError{
+				Job:     job,
+				Status:  job.Status,
+				Code:    job.Failure.Code,
+				Message: job.Failure.Error,
+			}
+		}
+
+		wait := interval
+		if poll == 0 {
+			predicted := time.Duration(job.PredictedExecutionTime) * time.Millisecond
+			if predicted > wait {
+				wait = predicted
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * waitOptions.Multiplier)
+		if interval > waitOptions.MaxInterval {
+			interval = waitOptions.MaxInterval
+		}
+	}
+}
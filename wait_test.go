@@ -0,0 +1,123 @@
+package ionq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+)
+
+func testWaitOptions() *WaitOptions {
+	return &WaitOptions{
+		Interval:    time.Millisecond,
+		MaxInterval: 5 * time.Millisecond,
+	}
+}
+
+func TestWaitForJobCompleted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	for _, status := range []JobStatus{JobStatusSubmitted, JobStatusReady, JobStatusRunning} {
+		newGock().
+			Get(fmt.Sprintf("%s/some-id", jobsPath)).
+			Reply(200).
+			JSON(&GetJobResponse{ID: "some-id", Status: status})
+	}
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(200).
+		JSON(&GetJobResponse{ID: "some-id", Status: "completed", Qubits: 3})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	job, err := client.WaitForJob(ctx, "some-id", testWaitOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if job.Status != "completed" || job.Qubits != 3 {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+
+	if !gock.IsDone() {
+		t.Fatal("expected every poll to be made")
+	}
+}
+
+func TestWaitForJobFailed(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	failedJob := GetJobResponse{ID: "some-id", Status: "failed"}
+	failedJob.Failure.Code = "CompilationError"
+	failedJob.Failure.Error = "circuit could not be compiled"
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(200).
+		JSON(&failedJob)
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	job, err := client.WaitForJob(ctx, "some-id", testWaitOptions())
+	if !errors.Is(err, ErrJobFailed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var jobErr *JobError
+	if !errors.As(err, &jobErr) {
+		t.Fatalf("expected *JobError, received %T", err)
+	}
+
+	if jobErr.Code != "CompilationError" || jobErr.Message != "circuit could not be compiled" {
+		t.Fatalf("unexpected job error: %+v", jobErr)
+	}
+
+	if job == nil || job.ID != "some-id" {
+		t.Fatalf("expected the failed job to be returned")
+	}
+}
+
+func TestWaitForJobCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id", jobsPath)).
+		Reply(200).
+		JSON(&GetJobResponse{ID: "some-id", Status: "canceled"})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := client.WaitForJob(ctx, "some-id", testWaitOptions())
+	if !errors.Is(err, ErrJobCanceled) || errors.Is(err, ErrJobFailed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestWaitForJobContextDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	defer gock.Off()
+
+	newGock().
+		Get(fmt.Sprintf("%s/some-id", jobsPath)).
+		Persist().
+		Reply(200).
+		JSON(&GetJobResponse{ID: "some-id", Status: "running", PredictedExecutionTime: 60000})
+
+	client := NewClient(myFakeEndpoint, myFakeAPIKey)
+	_, err := client.WaitForJob(ctx, "some-id", testWaitOptions())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}